@@ -5,18 +5,27 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"gioui.org/font"
+	"gioui.org/io/event"
 	"gioui.org/io/pointer"
 	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"github.com/hd-buddy/GioCalendarPicker/util"
 )
 
+// DatePicker is a date input box with a calendar popup. Create it with
+// NewDatePicker, or use the zero value, which starts out on today.
 type DatePicker struct {
 	Date      time.Time
 	IsOpen    bool
@@ -27,12 +36,110 @@ type DatePicker struct {
 	MonthBtn  widget.Clickable
 	YearBtn   widget.Clickable
 	Months    [12]widget.Clickable
-	Years     [20]widget.Clickable
-	ViewMode  string // "date", "month", or "year"
-	YearRange int    // Starting year for the year picker
-	TodayBtn  widget.Clickable
-	Editor    *widget.Editor
+	Years     []widget.Clickable // Year grid cells, allocated when first shown
+	Decades   []widget.Clickable // Decade grid cells, allocated when first shown
+	Centuries []widget.Clickable // Century grid cells, allocated when first shown
+	YearRange int                // Starting year for the year picker
+	// DecadeRange is the starting year for the decade picker.
+	DecadeRange int
+	// CenturyRange is the starting year for the century picker.
+	CenturyRange int
+	TodayBtn     widget.Clickable
+	Editor       *widget.Editor
+	// ParseErr holds the error from the last text typed into Editor that
+	// could not be parsed. The input box shows an error border while it is set.
+	ParseErr error
+	// Mode selects whether day clicks pick a single Date, a
+	// RangeStart/RangeEnd pair or a set of Selected dates.
+	Mode       SelectionMode
+	RangeStart time.Time
+	RangeEnd   time.Time   // zero while only the start of a range is picked
+	Selected   []time.Time // dates picked in MultiDate mode, in ascending order
+	// MinDate and MaxDate bound the dates that can be picked. A zero value
+	// leaves that side unbounded.
+	MinDate time.Time
+	MaxDate time.Time
+	// IsDisabled, when set, reports days that cannot be picked on top of
+	// MinDate and MaxDate. It is called with dates at midnight.
+	IsDisabled func(time.Time) bool
+	// ShowTime adds hour and minute controls under the calendar and keeps
+	// the time of day in Date, RangeStart, RangeEnd and Selected.
+	// ShowSeconds adds a seconds control and Use12Hour shows the hour with
+	// an AM/PM toggle.
+	ShowTime    bool
+	ShowSeconds bool
+	Use12Hour   bool
+	HourUp      widget.Clickable
+	HourDown    widget.Clickable
+	MinuteUp    widget.Clickable
+	MinuteDown  widget.Clickable
+	SecondUp    widget.Clickable
+	SecondDown  widget.Clickable
+	AmPmBtn     widget.Clickable
+	// FirstWeekday is the weekday shown in the first column of the day grid.
+	// It defaults to Monday: a DatePicker not made by NewDatePicker turns
+	// Sunday into Monday on its first frame, so start weeks on Sunday with
+	// WithFirstWeekday, or set it after the first frame.
+	FirstWeekday time.Weekday
+	// Locale supplies the month and weekday names and labels. Nil uses English.
+	Locale *Locale
+	// Theme supplies the colors. Nil uses LightTheme.
+	Theme *Theme
+	// ShowAdjacentDays fills the blank cells of the day grid with the days of
+	// the previous and next month. Picking one moves the calendar to its month.
+	ShowAdjacentDays bool
+	AdjacentDays     [14]widget.Clickable
+	// FixedWeeks always draws six week rows, so that the calendar keeps its
+	// height from month to month.
+	FixedWeeks bool
+	// Inline draws the calendar alone, always open, in place of the input box
+	// and popup. IsOpen is not used then, and no OpenEvent or CloseEvent is sent.
+	Inline bool
+	// Bounds is the area the popup has to fit in, usually the window, relative
+	// to the top-left corner of the DatePicker. The popup opens below or
+	// above the input box, whichever side has room. An empty Bounds always
+	// opens it below.
+	Bounds image.Rectangle
+	// ShowWeekNumbers adds a leading column with the ISO 8601 week number of
+	// each row. In DateRange and MultiDate mode clicking a number selects
+	// Monday to Sunday of that week; in SingleDate mode they are labels only.
+	ShowWeekNumbers bool
+	WeekBtns        [6]widget.Clickable
+	// Format is the time layout of a date in the input box. When empty,
+	// "02-Jan-2006" is used, followed by the time of day if ShowTime is set.
+	Format string
+	// InputFormats lists further layouts, such as ISODate, tried in order
+	// when the typed text does not match Format. Layouts without a time of
+	// day keep the time already selected.
+	InputFormats []string
+
+	editorText    string // text last written to Editor by the picker
+	editorFocused bool
+	hoverDate     time.Time // day under the pointer, used to preview a range
+	cursor        time.Time // keyboard focus cell in the open calendar
+	view          View
+	inputSize     image.Point     // size of the input box in the last frame
+	popupRect     image.Rectangle // bounds of the popup, relative to the input box
+	events        []Event         // changes not yet returned by Update
+	page          time.Time       // first day of the month the calendar shows
+	pageDate      time.Time       // Date when page was last moved to it
+	defaulted     bool            // setDefaults or NewDatePicker has run
+	location      *time.Location  // set by WithLocation for NewDatePicker
 }
+
+// SelectionMode controls what clicking a day in the calendar selects.
+type SelectionMode int
+
+const (
+	// SingleDate selects one date, stored in Date.
+	SingleDate SelectionMode = iota
+	// DateRange selects a start date with the first click and an end date
+	// with the second, stored in RangeStart and RangeEnd.
+	DateRange
+	// MultiDate toggles each clicked day in and out of Selected.
+	MultiDate
+)
+
 type (
 	C  = layout.Context
 	D  = layout.Dimensions
@@ -43,145 +150,691 @@ type (
 
 //go:embed assets/date.svg
 var DateIcon []byte
-var FlagDpDate bool
+
+// dateLayout is the default time layout of a date in the input box.
+const dateLayout = "02-Jan-2006"
+
+// Common layouts for DatePicker.Format and DatePicker.InputFormats.
+const (
+	ISODate      = "2006-01-02" // ISO 8601 calendar date
+	DayMonthYear = "02/01/2006" // dd/mm/yyyy
+	MonthDayYear = "01/02/2006" // mm/dd/yyyy
+)
+
+// rangeSeparators are the candidates, in order of preference, to sit between
+// the start and end date of a range in the input box, and listSeparators
+// between the dates of a MultiDate selection. The first that no date layout
+// contains is used, so that it never splits a date in two.
+var (
+	rangeSeparators = []string{" - ", " – ", " ~ "}
+	listSeparators  = []string{",", ";", "|"}
+)
+
+// popupWidth is the least width, in Dp, of the calendar popup unless Bounds
+// is narrower, and popupHeight the most height it takes.
+const (
+	popupWidth  = 320
+	popupHeight = 480
+)
+
+// scrimSize is half the side of the area, centered on the DatePicker, in
+// which presses outside the popup close it.
+const scrimSize = 1 << 20
 
 func (dp *DatePicker) Layout(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	if dp.Openbtn.Clicked(gtx) {
-		dp.IsOpen = !dp.IsOpen
-		dp.ViewMode = "date"
+	for {
+		_, ok := dp.Update(gtx)
+		if !ok {
+			break
+		}
 	}
-	if dp.TodayBtn.Clicked(gtx) {
-		dp.Date = time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, dp.Date.Location())
-		// dp.IsOpen = false
+	if dp.Inline {
+		return dp.inlineLayout(gtx, th)
 	}
 	DateIcon := util.LoadSvg(DateIcon)
-	dp.Editor.SetText(dp.Date.Format("02-Jan-2006"))
-	return layout.Stack{Alignment: layout.N}.Layout(gtx,
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			// gtx.Constraints.Min.X = gtx.Constraints.Max.X
-			inputBox, _ := util.LayoutInputBoxWithIcon(gtx, th, util.InputBox{
-				Editor:       dp.Editor,
-				BorderColor:  BlackColor,
-				CornerRadius: 6,
-				FontWeight:   font.SemiBold,
-				Hint:         dp.Date.Format("02-Jan-2006"),
-				TextColor:    BlackColor,
-				Size:         16,
-				Width:        unit.Dp(gtx.Constraints.Max.X),
-				Height:       42,
-				InInset:      layout.Inset{Left: 10, Top: 10, Bottom: 10, Right: 30},
-			}, util.Icon{
-				Height:     20,
-				Width:      20,
-				Icon1:      DateIcon,
-				IconButton: &dp.Openbtn,
-				Inset:      layout.Inset{Right: 10},
-			}, layout.E)
-
-			return inputBox
+	borderColor := dp.theme().Border
+	if dp.ParseErr != nil {
+		borderColor = dp.theme().Error
+	}
+	inputBox, _ := util.LayoutInputBoxWithIcon(gtx, th, util.InputBox{
+		Editor:       dp.Editor,
+		BorderColor:  borderColor,
+		CornerRadius: 6,
+		FontWeight:   font.SemiBold,
+		Hint:         dp.locale().Format(dp.Date, dp.textLayout()),
+		TextColor:    dp.theme().Text,
+		Size:         16,
+		Width:        unit.Dp(gtx.Constraints.Max.X),
+		Height:       42,
+		SingleLine:   true,
+		Submit:       true,
+		InInset:      layout.Inset{Left: 10, Top: 10, Bottom: 10, Right: 30},
+	}, util.Icon{
+		Height:      20,
+		Width:       20,
+		Icon1:       DateIcon,
+		IconButton:  &dp.Openbtn,
+		Description: dp.locale().OpenCalendar,
+		Inset:       layout.Inset{Right: 10},
+	}, layout.E)
+	dp.inputSize = inputBox.Size
+	if dp.IsOpen {
+		dp.popup(gtx, th)
+	}
+	return inputBox
+}
+
+// inlineLayout draws the calendar and time controls of an Inline DatePicker.
+func (dp *DatePicker) inlineLayout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Background{}.Layout(gtx, dp.background, func(gtx C) D {
+				return dp.calendarLayout(gtx, th)
+			})
 		}),
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			if dp.IsOpen {
-				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return layout.Spacer{Height: 50}.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return widget.Border{
-							Color:        LGrayColor,
-							Width:        unit.Dp(1),
-							CornerRadius: 6,
-						}.Layout(gtx, func(gtx C) D {
-							return widget.Border{
-								Color:        MGrayColor,
-								Width:        unit.Dp(1),
-								CornerRadius: 6,
-							}.Layout(gtx, func(gtx C) D {
-								return widget.Border{
-									Color:        GrayColor,
-									Width:        unit.Dp(1),
-									CornerRadius: 6,
-								}.Layout(gtx, func(gtx C) D {
-									return layout.UniformInset(unit.Dp(1)).Layout(gtx, func(gtx C) D {
-										return dp.calendarLayout(gtx, th)
-									})
-								})
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !dp.ShowTime {
+				return layout.Dimensions{}
+			}
+			return dp.timeLayout(gtx, th)
+		}),
+	)
+}
+
+// popup draws the calendar under the input box, on top of the rest of the
+// window. It is not part of the dimensions of the DatePicker.
+func (dp *DatePicker) popup(gtx layout.Context, th *material.Theme) {
+	macro := op.Record(gtx.Ops)
+
+	// Watch presses anywhere in the window, for Update to close the popup
+	// on those outside of it. They pass through to the widgets underneath.
+	scrim := clip.Rect{Min: image.Pt(-scrimSize, -scrimSize), Max: image.Pt(scrimSize, scrimSize)}.Push(gtx.Ops)
+	pass := pointer.PassOp{}.Push(gtx.Ops)
+	event.Op(gtx.Ops, &dp.popupRect)
+	pass.Pop()
+	scrim.Pop()
+
+	size := image.Pt(max(dp.inputSize.X, gtx.Dp(popupWidth)), gtx.Dp(popupHeight))
+	if !dp.Bounds.Empty() {
+		size = image.Pt(min(size.X, dp.Bounds.Dx()), min(size.Y, dp.Bounds.Dy()))
+	}
+	gtx.Constraints = layout.Constraints{Max: size}
+	content := op.Record(gtx.Ops)
+	dims := dp.popupLayout(gtx, th)
+	call := content.Stop()
+
+	offset := dp.placePopup(dims.Size, gtx.Dp(8))
+	trans := op.Offset(offset).Push(gtx.Ops)
+	call.Add(gtx.Ops)
+	trans.Pop()
+	dp.popupRect = image.Rectangle{Min: offset, Max: offset.Add(dims.Size)}
+	op.Defer(gtx.Ops, macro.Stop())
+}
+
+// placePopup returns the position of a popup of the given size, gap away
+// from the input box. It opens below and left-aligned with the input box,
+// flips above or right-aligned when that side of Bounds has no room, and is
+// then moved inside Bounds.
+func (dp *DatePicker) placePopup(size image.Point, gap int) image.Point {
+	pos := image.Pt(0, dp.inputSize.Y+gap)
+	b := dp.Bounds
+	if b.Empty() {
+		return pos
+	}
+	if pos.Y+size.Y > b.Max.Y {
+		above := -gap - size.Y
+		if above >= b.Min.Y || -b.Min.Y > b.Max.Y-dp.inputSize.Y {
+			pos.Y = above
+		}
+	}
+	if pos.X+size.X > b.Max.X {
+		pos.X = dp.inputSize.X - size.X
+	}
+	pos.X = max(min(pos.X, b.Max.X-size.X), b.Min.X)
+	pos.Y = max(min(pos.Y, b.Max.Y-size.Y), b.Min.Y)
+	return pos
+}
+
+// popupLayout draws the calendar, the time controls and the footer.
+func (dp *DatePicker) popupLayout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return widget.Border{
+				Color:        dp.theme().Shadow,
+				Width:        unit.Dp(1),
+				CornerRadius: 6,
+			}.Layout(gtx, func(gtx C) D {
+				return widget.Border{
+					Color:        dp.theme().Shadow,
+					Width:        unit.Dp(1),
+					CornerRadius: 6,
+				}.Layout(gtx, func(gtx C) D {
+					return widget.Border{
+						Color:        dp.theme().Border,
+						Width:        unit.Dp(1),
+						CornerRadius: 6,
+					}.Layout(gtx, func(gtx C) D {
+						return layout.UniformInset(unit.Dp(1)).Layout(gtx, func(gtx C) D {
+							return layout.Background{}.Layout(gtx, dp.background, func(gtx C) D {
+								return dp.calendarLayout(gtx, th)
 							})
 						})
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
-							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-								return util.LayoutText(gtx, th, util.Text{
-									Text:       "Created by Dhruv Hingu",
-									Size:       12,
-									TextColor:  BlackColor,
-									FontWeight: font.Bold,
-									Inset:      layout.UniformInset(unit.Dp(10)),
-								})
-							}),
-							layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-								return layout.Spacer{}.Layout(gtx)
-							}),
-							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-								return util.LayoutButton(gtx, th, util.Button{
-									Text:            "Go to Today",
-									TextColor:       color.NRGBA{R: 0, G: 0, B: 255, A: 255},
-									Size:            12,
-									FontWeight:      font.Bold,
-									BackgroundColor: Transparent,
-									BorderColor:     GrayColor,
-									CornerRadius:    6,
-									Button:          &dp.TodayBtn,
-									InInset:         layout.UniformInset(unit.Dp(10)),
-									OutInset:        layout.UniformInset(unit.Dp(10)),
-								})
-							}),
-						)
-					}),
-				)
+					})
+				})
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !dp.ShowTime {
+				return layout.Dimensions{}
 			}
-			return layout.Dimensions{}
+			return dp.timeLayout(gtx, th)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return util.LayoutText(gtx, th, util.Text{
+						Text:       "Created by Dhruv Hingu",
+						Size:       12,
+						TextColor:  dp.theme().Footer,
+						FontWeight: font.Bold,
+						Inset:      layout.UniformInset(unit.Dp(10)),
+					})
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layout.Spacer{}.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return util.LayoutButton(gtx, th, util.Button{
+						Text:            dp.locale().Today,
+						TextColor:       dp.theme().Today,
+						Size:            12,
+						FontWeight:      font.Bold,
+						BackgroundColor: util.Transparent,
+						BorderColor:     dp.theme().Border,
+						CornerRadius:    6,
+						Button:          &dp.TodayBtn,
+						InInset:         layout.UniformInset(unit.Dp(10)),
+						OutInset:        layout.UniformInset(unit.Dp(10)),
+					})
+				}),
+			)
 		}),
 	)
 }
 
-var btnText string
+// rangeSeparator returns the separator between the start and end date of a
+// range in the input box.
+func (dp *DatePicker) rangeSeparator() string {
+	return dp.separator(rangeSeparators)
+}
 
-func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+// listSeparator returns the separator between the dates of a MultiDate
+// selection in the input box. The dates are written with a space after it.
+func (dp *DatePicker) listSeparator() string {
+	return dp.separator(listSeparators)
+}
+
+// separator returns the first of seps found in none of the layouts the input
+// box formats and parses dates with, or the last one if they all are.
+func (dp *DatePicker) separator(seps []string) string {
+	layouts := append([]string{dp.textLayout()}, dp.InputFormats...)
+	for _, sep := range seps {
+		if !slices.ContainsFunc(layouts, func(layout string) bool { return strings.Contains(layout, sep) }) {
+			return sep
+		}
+	}
+	return seps[len(seps)-1]
+}
+
+// editorValue returns the selection formatted for the input box.
+func (dp *DatePicker) editorValue() string {
+	switch dp.Mode {
+	case DateRange:
+		if dp.RangeStart.IsZero() {
+			return ""
+		}
+		text := dp.locale().Format(dp.RangeStart, dp.textLayout()) + dp.rangeSeparator()
+		if !dp.RangeEnd.IsZero() {
+			text += dp.locale().Format(dp.RangeEnd, dp.textLayout())
+		}
+		return text
+	case MultiDate:
+		texts := make([]string, len(dp.Selected))
+		for i, date := range dp.Selected {
+			texts[i] = dp.locale().Format(date, dp.textLayout())
+		}
+		return strings.Join(texts, dp.listSeparator()+" ")
+	default:
+		return dp.locale().Format(dp.Date, dp.textLayout())
+	}
+}
+
+func (dp *DatePicker) theme() *Theme {
+	if dp.Theme == nil {
+		return &LightTheme
+	}
+	return dp.Theme
+}
+
+func (dp *DatePicker) locale() *Locale {
+	if dp.Locale == nil {
+		return &English
+	}
+	return dp.Locale
+}
+
+// textLayout returns the time layout of a single date in the input box.
+func (dp *DatePicker) textLayout() string {
+	if dp.Format != "" {
+		return dp.Format
+	}
+	if !dp.ShowTime {
+		return dateLayout
+	}
+	return dateLayout + " " + dp.clockLayout()
+}
+
+func (dp *DatePicker) clockLayout() string {
+	switch {
+	case dp.Use12Hour && dp.ShowSeconds:
+		return "03:04:05 PM"
+	case dp.Use12Hour:
+		return "03:04 PM"
+	case dp.ShowSeconds:
+		return "15:04:05"
+	default:
+		return "15:04"
+	}
+}
+
+// commitText parses text typed into the editor and makes it the selection.
+// Invalid text is left in the editor and reported through ParseErr.
+func (dp *DatePicker) commitText(text string) {
+	switch dp.Mode {
+	case DateRange:
+		sep := dp.rangeSeparator()
+		startText, endText, ok := strings.Cut(text, sep)
+		if !ok {
+			dp.ParseErr = fmt.Errorf("invalid range %q, expected two dates separated by %q", text, sep)
+			return
+		}
+		start, err := dp.parseDate(startText)
+		if err != nil {
+			dp.ParseErr = err
+			return
+		}
+		end, err := dp.parseDate(endText)
+		if err != nil {
+			dp.ParseErr = err
+			return
+		}
+		if end.Before(start) {
+			start, end = end, start
+		}
+		dp.RangeStart, dp.RangeEnd = start, end
+		dp.Date = start
+	case MultiDate:
+		var selected []time.Time
+		for _, text := range strings.Split(text, dp.listSeparator()) {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			date, err := dp.parseDate(text)
+			if err != nil {
+				dp.ParseErr = err
+				return
+			}
+			selected = append(selected, date)
+		}
+		dp.Selected = nil
+		for _, date := range selected {
+			if !dp.isSelected(date) {
+				dp.toggleSelected(date)
+			}
+		}
+	default:
+		date, err := dp.parseDate(text)
+		if err != nil {
+			dp.ParseErr = err
+			return
+		}
+		dp.Date = date
+	}
+	dp.ParseErr = nil
+	dp.editorText = dp.editorValue()
+	dp.Editor.SetText(dp.editorText)
+}
+
+func (dp *DatePicker) parseDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	layouts := append([]string{dp.textLayout()}, dp.InputFormats...)
+	if dp.ShowTime && dp.Format == "" {
+		// Accept a bare date and keep the time of day already selected.
+		layouts = append(layouts, dateLayout)
+	}
+	for _, layout := range layouts {
+		date, err := dp.locale().Parse(layout, text, dp.Date.Location())
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "04") {
+			date = dp.withTime(date)
+		}
+		if dp.outOfBounds(date) {
+			return time.Time{}, fmt.Errorf("date %s is outside the allowed range", dp.locale().Format(date, dp.textLayout()))
+		}
+		if dp.dayDisabled(date) {
+			return time.Time{}, fmt.Errorf("date %s is not available", dp.locale().Format(date, dp.textLayout()))
+		}
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected a date like %s", text, dp.locale().Format(dp.Date, dp.textLayout()))
+}
+
+// selectDay applies a click on day according to the selection mode.
+func (dp *DatePicker) selectDay(day time.Time) {
+	day = dp.withTime(day)
+	dp.Date = day
+	dp.cursor = day
+	switch dp.Mode {
+	case DateRange:
+		if dp.RangeStart.IsZero() || !dp.RangeEnd.IsZero() {
+			dp.RangeStart, dp.RangeEnd = day, time.Time{}
+			return
+		}
+		if day.Before(dp.RangeStart) {
+			dp.RangeStart, dp.RangeEnd = day, dp.RangeStart
+		} else {
+			dp.RangeEnd = day
+		}
+		dp.IsOpen = false
+	case MultiDate:
+		dp.toggleSelected(day)
+	default:
+		dp.IsOpen = false
+	}
+}
+
+// toggleSelected adds day to Selected, or removes it if it is already there.
+func (dp *DatePicker) toggleSelected(day time.Time) {
+	for i, date := range dp.Selected {
+		if sameDay(date, day) {
+			dp.Selected = append(dp.Selected[:i], dp.Selected[i+1:]...)
+			return
+		}
+	}
+	i := sort.Search(len(dp.Selected), func(i int) bool { return dp.Selected[i].After(day) })
+	dp.Selected = append(dp.Selected, time.Time{})
+	copy(dp.Selected[i+1:], dp.Selected[i:])
+	dp.Selected[i] = day
+}
+
+// background fills the popup with the theme background.
+func (dp *DatePicker) background(gtx layout.Context) layout.Dimensions {
+	defer clip.UniformRRect(image.Rectangle{Max: gtx.Constraints.Min}, gtx.Dp(6)).Push(gtx.Ops).Pop()
+	paint.Fill(gtx.Ops, dp.theme().Background)
+	return layout.Dimensions{Size: gtx.Constraints.Min}
+}
+
+// describe returns the screen reader description of a calendar cell.
+func (dp *DatePicker) describe(name string, selected, disabled bool) string {
+	if selected {
+		name += ", " + dp.locale().Selected
+	}
+	if disabled {
+		name += ", " + dp.locale().Unavailable
+	}
+	return name
+}
+
+// selectMonth shows the days of month in year.
+func (dp *DatePicker) selectMonth(year int, month time.Month) {
+	dp.page = time.Date(year, month, 1, 0, 0, 0, 0, dp.Date.Location())
+	dp.view = DayView
+	dp.cursor = dp.page
+}
+
+// selectYear shows the months of year.
+func (dp *DatePicker) selectYear(year int) {
+	dp.selectMonth(year, dp.page.Month())
+	dp.view = MonthView
+}
+
+// syncPage shows the month of Date when Date changed since the page was
+// last moved to it, so that paging the calendar leaves Date alone.
+func (dp *DatePicker) syncPage() {
+	if dp.page.IsZero() || !dp.Date.Equal(dp.pageDate) {
+		dp.page = monthOf(dp.Date)
+		dp.pageDate = dp.Date
+	}
+}
+
+// monthOf returns the first day of the month of t.
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// isSelected reports whether day is drawn with the selected-date border.
+func (dp *DatePicker) isSelected(day time.Time) bool {
+	switch dp.Mode {
+	case DateRange:
+		return sameDay(day, dp.RangeStart) || sameDay(day, dp.RangeEnd)
+	case MultiDate:
+		for _, date := range dp.Selected {
+			if sameDay(day, date) {
+				return true
+			}
+		}
+		return false
+	default:
+		return sameDay(day, dp.Date)
+	}
+}
+
+// inRange reports whether day lies within the picked range. While only the
+// start is picked, the range extends to the hovered day as a preview.
+func (dp *DatePicker) inRange(day time.Time) bool {
+	if dp.Mode != DateRange || dp.RangeStart.IsZero() {
+		return false
+	}
+	start, end := dp.RangeStart, dp.RangeEnd
+	if end.IsZero() {
+		end = dp.hoverDate
+	}
+	if end.IsZero() {
+		return false
+	}
+	if end.Before(start) {
+		start, end = end, start
+	}
+	return !day.Before(truncateDay(start)) && !day.After(truncateDay(end))
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// addMonths moves t by n months. When the target month is too short for the
+// day of t, it lands on the last day of that month instead of overflowing
+// into the next one as time.Time.AddDate does.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	day := min(t.Day(), first.AddDate(0, 1, -1).Day())
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// addYears moves t by n years, from 29 February to 28 February in common years.
+func addYears(t time.Time, n int) time.Time {
+	return addMonths(t, 12*n)
+}
+
+// withClock returns day at the time of day of clock.
+func withClock(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+}
+
+// withTime carries the selected time of day over to day when ShowTime is
+// set, and truncates day to midnight otherwise.
+func (dp *DatePicker) withTime(day time.Time) time.Time {
+	if !dp.ShowTime {
+		return truncateDay(day)
+	}
+	return withClock(day, dp.Date)
+}
 
-	if dp.PrevBtn.Clicked(gtx) {
-		switch dp.ViewMode {
-		case "date":
-			dp.Date = dp.Date.AddDate(0, -1, 0)
-		case "month":
-			dp.Date = dp.Date.AddDate(-1, 0, 0)
-		case "year":
-			dp.YearRange -= 20
+// setClock sets the time of day of Date, wrapping each field within its range,
+// and carries it over to the picked range ends and Selected dates.
+func (dp *DatePicker) setClock(hour, minute, second int) {
+	hour = (hour%24 + 24) % 24
+	minute = (minute%60 + 60) % 60
+	second = (second%60 + 60) % 60
+	dp.Date = time.Date(dp.Date.Year(), dp.Date.Month(), dp.Date.Day(), hour, minute, second, 0, dp.Date.Location())
+	if !dp.RangeStart.IsZero() {
+		dp.RangeStart = withClock(dp.RangeStart, dp.Date)
+	}
+	if !dp.RangeEnd.IsZero() {
+		dp.RangeEnd = withClock(dp.RangeEnd, dp.Date)
+	}
+	for i, date := range dp.Selected {
+		dp.Selected[i] = withClock(date, dp.Date)
+	}
+}
+
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
+
+func sameDay(a, b time.Time) bool {
+	return !a.IsZero() && !b.IsZero() && a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// outOfBounds reports whether day falls outside MinDate and MaxDate.
+func (dp *DatePicker) outOfBounds(day time.Time) bool {
+	day = truncateDay(day)
+	return (!dp.MinDate.IsZero() && day.Before(truncateDay(dp.MinDate))) ||
+		(!dp.MaxDate.IsZero() && day.After(truncateDay(dp.MaxDate)))
+}
+
+// dayDisabled reports whether day is out of bounds or rejected by IsDisabled.
+func (dp *DatePicker) dayDisabled(day time.Time) bool {
+	return dp.outOfBounds(day) || (dp.IsDisabled != nil && dp.IsDisabled(truncateDay(day)))
+}
+
+// monthDisabled reports whether no day of the month can be picked.
+func (dp *DatePicker) monthDisabled(year int, month time.Month) bool {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, dp.Date.Location())
+	last := first.AddDate(0, 1, -1)
+	if (!dp.MinDate.IsZero() && last.Before(truncateDay(dp.MinDate))) ||
+		(!dp.MaxDate.IsZero() && first.After(truncateDay(dp.MaxDate))) {
+		return true
+	}
+	if dp.IsDisabled == nil {
+		return false
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !dp.dayDisabled(day) {
+			return false
 		}
 	}
-	if dp.NextBtn.Clicked(gtx) {
-		switch dp.ViewMode {
-		case "date":
-			dp.Date = dp.Date.AddDate(0, 1, 0)
-		case "month":
-			dp.Date = dp.Date.AddDate(1, 0, 0)
-		case "year":
-			dp.YearRange += 20
+	return true
+}
+
+// yearDisabled reports whether no day of the year can be picked.
+func (dp *DatePicker) yearDisabled(year int) bool {
+	if (!dp.MinDate.IsZero() && year < dp.MinDate.Year()) ||
+		(!dp.MaxDate.IsZero() && year > dp.MaxDate.Year()) {
+		return true
+	}
+	if dp.IsDisabled == nil {
+		return false
+	}
+	for month := time.January; month <= time.December; month++ {
+		if !dp.monthDisabled(year, month) {
+			return false
 		}
 	}
-	if dp.MonthBtn.Clicked(gtx) {
-		dp.ViewMode = "month"
+	return true
+}
+
+// spanDisabled reports whether no day of the span years starting in year
+// start can be picked. Spans longer than a year are only checked against
+// MinDate and MaxDate, since asking IsDisabled about every day of a decade
+// or century on each frame is too slow.
+func (dp *DatePicker) spanDisabled(start, span int) bool {
+	if span == 1 {
+		return dp.yearDisabled(start)
+	}
+	return (!dp.MinDate.IsZero() && start+span <= dp.MinDate.Year()) ||
+		(!dp.MaxDate.IsZero() && start > dp.MaxDate.Year())
+}
+
+// clamp moves t inside MinDate and MaxDate, and then to the nearest day
+// within a year that IsDisabled accepts. If there is none, the bounded t
+// is returned as is.
+func (dp *DatePicker) clamp(t time.Time) time.Time {
+	if !dp.MinDate.IsZero() && truncateDay(t).Before(truncateDay(dp.MinDate)) {
+		t = withClock(dp.MinDate, t)
+	}
+	if !dp.MaxDate.IsZero() && truncateDay(t).After(truncateDay(dp.MaxDate)) {
+		t = withClock(dp.MaxDate, t)
+	}
+	if !dp.dayDisabled(t) {
+		return t
+	}
+	for i := 1; i <= 366; i++ {
+		if next := t.AddDate(0, 0, i); !dp.dayDisabled(next) {
+			return next
+		}
+		if prev := t.AddDate(0, 0, -i); !dp.dayDisabled(prev) {
+			return prev
+		}
 	}
-	if dp.YearBtn.Clicked(gtx) {
-		dp.ViewMode = "year"
-		dp.YearRange = dp.Date.Year() - 10
+	return t
+}
+
+// canPrev reports whether PrevBtn leads to a page with anything to pick.
+func (dp *DatePicker) canPrev() bool {
+	switch dp.view {
+	case DayView:
+		prev := dp.page.AddDate(0, -1, 0)
+		return !dp.monthDisabled(prev.Year(), prev.Month())
+	case MonthView:
+		return !dp.yearDisabled(dp.page.Year() - 1)
+	case YearView, DecadeView, CenturyView:
+		n := gridCells * dp.view.span()
+		return !dp.spanDisabled(*dp.firstYear(dp.view)-n, n)
+	}
+	return false
+}
+
+// canNext reports whether NextBtn leads to a page with anything to pick.
+func (dp *DatePicker) canNext() bool {
+	switch dp.view {
+	case DayView:
+		next := dp.page.AddDate(0, 1, 0)
+		return !dp.monthDisabled(next.Year(), next.Month())
+	case MonthView:
+		return !dp.yearDisabled(dp.page.Year() + 1)
+	case YearView, DecadeView, CenturyView:
+		n := gridCells * dp.view.span()
+		return !dp.spanDisabled(*dp.firstYear(dp.view)+n, n)
+	}
+	return false
+}
+
+func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	prevDescription, nextDescription := dp.locale().PrevMonth, dp.locale().NextMonth
+	switch dp.view {
+	case MonthView:
+		prevDescription, nextDescription = dp.locale().PrevYear, dp.locale().NextYear
+	case YearView, DecadeView, CenturyView:
+		prevDescription, nextDescription = dp.locale().PrevYears, dp.locale().NextYears
 	}
 
 	gtx.Constraints.Min.Y = 300
-	return layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+	dims := layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return widget.Border{
-			Color:        BlackColor,
+			Color:        dp.theme().Border,
 			CornerRadius: unit.Dp(6),
 			// Width:        unit.Dp(1),
 		}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
@@ -189,15 +842,21 @@ func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) lay
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							textColor := dp.theme().Header
+							if !dp.canPrev() {
+								gtx = gtx.Disabled()
+								textColor = dp.theme().Disabled
+							}
 							return util.LayoutButton(gtx, th, util.Button{
 								Text:            "<",
-								TextColor:       BlackColor,
+								TextColor:       textColor,
 								Size:            20,
 								FontWeight:      font.Bold,
-								BackgroundColor: Transparent,
-								BorderColor:     GrayColor,
+								BackgroundColor: util.Transparent,
+								BorderColor:     dp.theme().Border,
 								CornerRadius:    4,
 								Button:          &dp.PrevBtn,
+								Description:     prevDescription,
 								InInset:         layout.Inset{Left: 15, Right: 15, Top: 5, Bottom: 5},
 							})
 
@@ -206,40 +865,36 @@ func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) lay
 							return layout.Spacer{}.Layout(gtx)
 						}),
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-							btnText = ""
-							switch dp.ViewMode {
-							case "date":
-								btnText = dp.Date.Format("January 2006")
-							case "month":
-								btnText = dp.Date.Format("2006")
-							case "year":
-								btnText = fmt.Sprintf("%d-%d", dp.YearRange, dp.YearRange+19)
+							if dp.view != DayView {
+								return dp.title(gtx, th)
 							}
 							return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
 								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 									return util.LayoutButton(gtx, th, util.Button{
-										Text:            dp.Date.Format("Jan"),
-										TextColor:       BlackColor,
+										Text:            dp.locale().ShortMonths[dp.page.Month()-1],
+										TextColor:       dp.theme().Header,
 										Size:            20,
 										FontWeight:      font.Bold,
-										BackgroundColor: Transparent,
-										BorderColor:     GrayColor,
+										BackgroundColor: util.Transparent,
+										BorderColor:     dp.theme().Border,
 										CornerRadius:    4,
 										Button:          &dp.MonthBtn,
+										Description:     dp.locale().ChooseMonth,
 										InInset:         layout.Inset{Left: 15, Right: 15, Top: 5, Bottom: 5},
 									})
 								}),
 								layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
 								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 									return util.LayoutButton(gtx, th, util.Button{
-										Text:            dp.Date.Format("2006"),
-										TextColor:       BlackColor,
+										Text:            dp.page.Format("2006"),
+										TextColor:       dp.theme().Header,
 										Size:            20,
 										FontWeight:      font.Bold,
-										BackgroundColor: Transparent,
-										BorderColor:     GrayColor,
+										BackgroundColor: util.Transparent,
+										BorderColor:     dp.theme().Border,
 										CornerRadius:    4,
 										Button:          &dp.YearBtn,
+										Description:     dp.locale().ChooseYear,
 										InInset:         layout.Inset{Left: 15, Right: 15, Top: 5, Bottom: 5},
 									})
 								}),
@@ -250,15 +905,21 @@ func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) lay
 						}),
 
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							textColor := dp.theme().Header
+							if !dp.canNext() {
+								gtx = gtx.Disabled()
+								textColor = dp.theme().Disabled
+							}
 							return util.LayoutButton(gtx, th, util.Button{
 								Text:            ">",
-								TextColor:       BlackColor,
+								TextColor:       textColor,
 								Size:            20,
 								FontWeight:      font.Bold,
-								BackgroundColor: Transparent,
-								BorderColor:     GrayColor,
+								BackgroundColor: util.Transparent,
+								BorderColor:     dp.theme().Border,
 								CornerRadius:    4,
 								Button:          &dp.NextBtn,
+								Description:     nextDescription,
 								InInset:         layout.Inset{Left: 15, Right: 15, Top: 5, Bottom: 5},
 							})
 
@@ -267,149 +928,324 @@ func (dp *DatePicker) calendarLayout(gtx layout.Context, th *material.Theme) lay
 				}),
 				layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					switch dp.ViewMode {
-					case "date":
-						return dp.daysGrid(gtx, th)
-					case "month":
+					switch dp.view {
+					case MonthView:
 						return dp.monthGrid(gtx, th)
-					case "year":
+					case YearView, DecadeView, CenturyView:
 						return dp.yearGrid(gtx, th)
 					default:
-						return layout.Dimensions{}
+						return dp.daysGrid(gtx, th)
 					}
 				}),
 			)
 		})
 	})
 
+	// Catch presses on the cells for Update to move the keyboard focus back
+	// to the calendar. The area passes pointer events through to the cells
+	// underneath.
+	defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+	defer pointer.PassOp{}.Push(gtx.Ops).Pop()
+	event.Op(gtx.Ops, dp)
+	return dims
 }
 
-var hoverbg color.NRGBA
-var textcolor color.NRGBA
-var bordercolor color.NRGBA
+// title draws the header button that zooms out of the month, year, decade
+// and century grids.
+func (dp *DatePicker) title(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	var text, description string
+	switch dp.view {
+	case MonthView:
+		text, description = strconv.Itoa(dp.page.Year()), dp.locale().ChooseYear
+	case YearView, DecadeView, CenturyView:
+		first := *dp.firstYear(dp.view)
+		text = fmt.Sprintf("%d - %d", first, first+gridCells*dp.view.span()-1)
+		switch dp.view {
+		case YearView:
+			description = dp.locale().ChooseDecade
+		case DecadeView:
+			description = dp.locale().ChooseCentury
+		}
+	}
+	if dp.view == CenturyView {
+		gtx = gtx.Disabled()
+	}
+	return util.LayoutButton(gtx, th, util.Button{
+		Text:            text,
+		TextColor:       dp.theme().Header,
+		Size:            20,
+		FontWeight:      font.Bold,
+		BackgroundColor: util.Transparent,
+		BorderColor:     dp.theme().Border,
+		CornerRadius:    4,
+		Button:          &dp.YearBtn,
+		Description:     description,
+		InInset:         layout.Inset{Left: 15, Right: 15, Top: 5, Bottom: 5},
+	})
+}
+
+// dayGrid returns the first day the day grid shows and its number of rows.
+func (dp *DatePicker) dayGrid() (start time.Time, rows int) {
+	firstDay := dp.page
+	daysInMonth := firstDay.AddDate(0, 1, -1).Day()
+	startOffset := (int(firstDay.Weekday()) - int(dp.FirstWeekday) + 7) % 7
+	rows = (startOffset + daysInMonth + 6) / 7
+	if dp.FixedWeeks {
+		rows = 6
+	}
+	return firstDay.AddDate(0, 0, -startOffset), rows
+}
+
+// dayButton returns the clickable of cell i of the day grid, which shows day,
+// or nil when the cell is left blank.
+func (dp *DatePicker) dayButton(i int, day time.Time) *widget.Clickable {
+	switch {
+	case sameMonth(day, dp.page):
+		return &dp.Days[day.Day()-1]
+	case !dp.ShowAdjacentDays:
+		return nil
+	case day.Before(dp.page):
+		// Days of the previous month only fill the first row.
+		return &dp.AdjacentDays[i]
+	default:
+		daysInMonth := day.AddDate(0, 0, -day.Day()).Day()
+		return &dp.AdjacentDays[i-daysInMonth]
+	}
+}
 
 func (dp *DatePicker) daysGrid(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	firstDay := time.Date(dp.Date.Year(), dp.Date.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
-	daysInMonth := 32 - time.Date(dp.Date.Year(), dp.Date.Month(), 32, 0, 0, 0, 0, dp.Date.Location()).Day()
+	gridStart, rows := dp.dayGrid()
 	totalspaceX := gtx.Constraints.Max.X
 	totalspaceY := gtx.Constraints.Max.Y - 100
-	startOffset := int(firstDay.Weekday())
-	if startOffset == 0 {
-		startOffset = 7
+	weekColumnX := 0
+	if dp.ShowWeekNumbers {
+		weekColumnX = totalspaceX / 8
+		totalspaceX -= weekColumnX
+	}
+
+	dp.hoverDate = time.Time{}
+	for i := 0; i < rows*7; i++ {
+		day := gridStart.AddDate(0, 0, i)
+		if button := dp.dayButton(i, day); button != nil && button.Hovered() && !dp.dayDisabled(day) {
+			dp.hoverDate = day
+		}
 	}
-	startOffset--
 
 	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Mon", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Tue", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Wed", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Thu", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Fri", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Sat", totalspaceX, totalspaceY)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					return DateSpacedLayout(gtx, th, "Sun", totalspaceX, totalspaceY)
-				}),
-			)
+			names := dp.locale().ShortWeekdays
+			if totalspaceX/7 < gtx.Dp(unit.Dp(40)) {
+				names = dp.locale().NarrowWeekdays
+			}
+			var headers []layout.FlexChild
+			if dp.ShowWeekNumbers {
+				headers = append(headers, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints = layout.Exact(image.Pt(weekColumnX, totalspaceY/7))
+					return centeredText(gtx, th, dp.locale().Week, dp.theme().Header)
+				}))
+			}
+			for col := 0; col < 7; col++ {
+				weekday := (dp.FirstWeekday + time.Weekday(col)) % 7
+				headers = append(headers, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return DateSpacedLayout(gtx, th, names[weekday], dp.theme().Header, totalspaceX, totalspaceY)
+				}))
+			}
+			return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx, headers...)
 		}),
 		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			var children []layout.FlexChild
-			day := 1
-			for week := 0; week < 6; week++ {
+			for week := 0; week < rows; week++ {
+				weekStart := gridStart.AddDate(0, 0, 7*week)
 				children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if !dp.ShowWeekNumbers {
+								return layout.Dimensions{}
+							}
+							gtx.Constraints = layout.Exact(image.Pt(weekColumnX, totalspaceY/7))
+							return dp.weekNumber(gtx, th, week, weekStart)
+						}),
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 							var weekDays []layout.FlexChild
 							for weekday := 0; weekday < 7; weekday++ {
-								if (week == 0 && weekday < startOffset) || day > daysInMonth {
-									gtx.Constraints.Min.X = totalspaceX / 7
-									gtx.Constraints.Max.X = totalspaceX / 7
-									gtx.Constraints.Min.Y = totalspaceY / 7
-									gtx.Constraints.Max.Y = totalspaceY / 7
+								i := week*7 + weekday
+								currentDate := gridStart.AddDate(0, 0, i)
+								button := dp.dayButton(i, currentDate)
+								if button == nil {
 									weekDays = append(weekDays, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 										return D{Size: image.Point{X: totalspaceX / 7, Y: totalspaceY / 7}}
 									}))
-								} else {
-									currentDay := day
-									currentDate := time.Date(dp.Date.Year(), dp.Date.Month(), currentDay, 0, 0, 0, 0, dp.Date.Location())
-
-									weekDays = append(weekDays, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-										if dp.Days[currentDay-1].Clicked(gtx) {
-											dp.Date = currentDate
-											dp.IsOpen = false
-										}
-
-										textcolor = BlackColor
-
-										if dp.Days[currentDay-1].Hovered() {
-											hoverbg = MGrayColor
-											pointer.CursorPointer.Add(gtx.Ops)
-										} else {
-											hoverbg = Transparent
-											pointer.CursorDefault.Add(gtx.Ops)
-										}
-
-										bordercolor = Transparent
-										if currentDate.Equal(dp.Date) {
-											bordercolor = RedColor
-										}
-
-										gtx.Constraints.Max.X = totalspaceX / 7
-										gtx.Constraints.Min.X = totalspaceX / 7
-										gtx.Constraints.Min.Y = totalspaceY / 7
-										gtx.Constraints.Max.Y = totalspaceY / 7
-										return util.LayoutButton(gtx, th, util.Button{
-											Text:            fmt.Sprintf("%d", currentDay),
-											Button:          &dp.Days[currentDay-1],
-											TextColor:       textcolor,
-											Size:            12,
-											FontWeight:      font.Bold,
-											BackgroundColor: hoverbg,
-											CornerRadius:    4,
-											BorderColor:     bordercolor,
-										})
-									}))
-									day++
+									continue
 								}
+								weekDays = append(weekDays, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									gtx.Constraints = layout.Exact(image.Pt(totalspaceX/7, totalspaceY/7))
+									return dp.dayCell(gtx, th, currentDate, button)
+								}))
 							}
 							return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx, weekDays...)
 						}),
 					)
 				}))
-				if day > daysInMonth {
-					break
-				}
 			}
 			return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceEvenly}.Layout(gtx, children...)
 		}),
 	)
 }
 
-func DateSpacedLayout(gtx C, th *mt, name string, totalspaceX int, totalspaceY int) D {
+// dayCell draws the button of day in the day grid, filling the constraints.
+func (dp *DatePicker) dayCell(gtx layout.Context, th *material.Theme, day time.Time, button *widget.Clickable) layout.Dimensions {
+	adjacent := !sameMonth(day, dp.page)
+	disabled := dp.dayDisabled(day)
+	focused := gtx.Focused(dp) && sameDay(day, dp.cursor)
+
+	t := dp.theme()
+	today := sameDay(day, time.Now().In(day.Location()))
+	textColor := t.Text
+	if weekday := day.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		textColor = t.Weekend
+	}
+	if today {
+		textColor = t.Today
+	}
+	if adjacent {
+		textColor = t.Adjacent
+	}
+
+	background := util.Transparent
+	if disabled {
+		gtx = gtx.Disabled()
+		textColor = t.Disabled
+		background = t.DisabledBackground
+	} else if button.Hovered() {
+		background = t.Hover
+		pointer.CursorPointer.Add(gtx.Ops)
+	} else if dp.inRange(day) {
+		background = t.Range
+		pointer.CursorDefault.Add(gtx.Ops)
+	} else {
+		pointer.CursorDefault.Add(gtx.Ops)
+	}
+
+	borderColor := util.Transparent
+	if dp.isSelected(day) {
+		borderColor = t.Selected
+	}
+	if focused {
+		borderColor = t.Focus
+	}
+
+	cell := func(gtx layout.Context) layout.Dimensions {
+		return util.LayoutButton(gtx, th, util.Button{
+			Text:            strconv.Itoa(day.Day()),
+			Button:          button,
+			Description:     dp.describe(dp.locale().Format(day, dp.locale().DateLayout), dp.isSelected(day), disabled),
+			TextColor:       textColor,
+			Size:            12,
+			FontWeight:      font.Bold,
+			BackgroundColor: background,
+			CornerRadius:    4,
+			BorderColor:     borderColor,
+		})
+	}
+	if !today {
+		return cell(gtx)
+	}
+	return layout.Stack{Alignment: layout.S}.Layout(gtx,
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			// Stacked drops the minimum size; keep the button filling the cell.
+			gtx.Constraints.Min = gtx.Constraints.Max
+			return cell(gtx)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(4)}.Layout(gtx, dp.todayMarker)
+		}),
+	)
+}
+
+// todayMarker draws the dot under the number of today's cell.
+func (dp *DatePicker) todayMarker(gtx layout.Context) layout.Dimensions {
+	size := gtx.Dp(unit.Dp(4))
+	paint.FillShape(gtx.Ops, dp.theme().TodayMarker, clip.Ellipse{Max: image.Pt(size, size)}.Op(gtx.Ops))
+	return layout.Dimensions{Size: image.Pt(size, size)}
+}
+
+// isoWeek returns the Monday of the ISO 8601 week of the day grid row
+// starting at rowStart, which is the week its Thursday belongs to.
+func (dp *DatePicker) isoWeek(rowStart time.Time) time.Time {
+	thursday := rowStart.AddDate(0, 0, (int(time.Thursday)-int(dp.FirstWeekday)+7)%7)
+	return thursday.AddDate(0, 0, -3)
+}
+
+// weekNumber draws the ISO week number of the day grid row starting at
+// rowStart. In DateRange and MultiDate mode it selects the week when clicked.
+func (dp *DatePicker) weekNumber(gtx layout.Context, th *material.Theme, row int, rowStart time.Time) layout.Dimensions {
+	_, week := dp.isoWeek(rowStart).ISOWeek()
+	if dp.Mode != DateRange && dp.Mode != MultiDate {
+		return centeredText(gtx, th, strconv.Itoa(week), dp.theme().Header)
+	}
+	button := &dp.WeekBtns[row]
+	background := util.Transparent
+	if button.Hovered() {
+		background = dp.theme().Hover
+	}
+	return util.LayoutButton(gtx, th, util.Button{
+		Text:            strconv.Itoa(week),
+		Button:          button,
+		TextColor:       dp.theme().Header,
+		Size:            12,
+		FontWeight:      font.Bold,
+		BackgroundColor: background,
+		CornerRadius:    4,
+	})
+}
+
+// selectWeek selects the seven days from monday: as the range in DateRange
+// mode, or toggled together in MultiDate mode.
+func (dp *DatePicker) selectWeek(monday time.Time) {
+	var days []time.Time
+	for i := 0; i < 7; i++ {
+		if day := monday.AddDate(0, 0, i); !dp.dayDisabled(day) {
+			days = append(days, dp.withTime(day))
+		}
+	}
+	if len(days) == 0 {
+		return
+	}
+	switch dp.Mode {
+	case DateRange:
+		dp.RangeStart, dp.RangeEnd = days[0], days[len(days)-1]
+		dp.Date = days[0]
+		dp.IsOpen = false
+	case MultiDate:
+		allSelected := true
+		for _, day := range days {
+			allSelected = allSelected && dp.isSelected(day)
+		}
+		for _, day := range days {
+			if allSelected || !dp.isSelected(day) {
+				dp.toggleSelected(day)
+			}
+		}
+	}
+}
+
+func DateSpacedLayout(gtx C, th *mt, name string, textColor color.NRGBA, totalspaceX int, totalspaceY int) D {
 	gtx.Constraints.Max.X = totalspaceX / 7
 	gtx.Constraints.Min.X = totalspaceX / 7
 	gtx.Constraints.Min.Y = totalspaceY / 7
 	gtx.Constraints.Max.Y = totalspaceY / 7
+	return centeredText(gtx, th, name, textColor)
+}
+
+// centeredText draws text in the middle of the constraints.
+func centeredText(gtx C, th *mt, text string, textColor color.NRGBA) D {
 	return layout.Center.Layout(gtx, func(gtx C) D {
 		return util.LayoutText(gtx, th, util.Text{
-			Text:       name,
+			Text:       text,
 			Size:       14,
 			FontWeight: font.Bold,
-			TextColor:  BlackColor,
+			TextColor:  textColor,
 		})
 	})
 }
@@ -417,7 +1253,7 @@ func DateSpacedLayout(gtx C, th *mt, name string, totalspaceX int, totalspaceY i
 func (dp *DatePicker) monthGrid(gtx layout.Context, th *material.Theme) layout.Dimensions {
 	totalspaceX := gtx.Constraints.Max.X
 	totalspaceY := gtx.Constraints.Max.Y - 100
-	months := []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	months := dp.locale().ShortMonths
 	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			var rows []layout.FlexChild
@@ -426,16 +1262,23 @@ func (dp *DatePicker) monthGrid(gtx layout.Context, th *material.Theme) layout.D
 					var monthButtons []layout.FlexChild
 					for col := 0; col < 4; col++ {
 						i := row*4 + col
+						month := time.Date(dp.page.Year(), time.Month(i+1), 1, 0, 0, 0, 0, dp.Date.Location())
 						monthButtons = append(monthButtons, layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-							if dp.Months[i].Clicked(gtx) {
-								dp.Date = time.Date(dp.Date.Year(), time.Month(i+1), 1, 0, 0, 0, 0, dp.Date.Location())
-								dp.ViewMode = "date"
+							disabled := dp.monthDisabled(dp.page.Year(), time.Month(i+1))
+							borderColor := util.Transparent
+							if gtx.Focused(dp) && dp.cursor.Month() == time.Month(i+1) {
+								borderColor = dp.theme().Focus
 							}
-							if dp.Months[i].Hovered() {
-								hoverbg = MGrayColor
+							textColor := dp.theme().Text
+							background := util.Transparent
+							if disabled {
+								gtx = gtx.Disabled()
+								textColor = dp.theme().Disabled
+								background = dp.theme().DisabledBackground
+							} else if dp.Months[i].Hovered() {
+								background = dp.theme().Hover
 								pointer.CursorPointer.Add(gtx.Ops)
 							} else {
-								hoverbg = Transparent
 								pointer.CursorDefault.Add(gtx.Ops)
 							}
 							gtx.Constraints.Min.X = totalspaceX / 4
@@ -445,11 +1288,13 @@ func (dp *DatePicker) monthGrid(gtx layout.Context, th *material.Theme) layout.D
 							return util.LayoutButton(gtx, th, util.Button{
 								Text:            months[i],
 								Button:          &dp.Months[i],
-								TextColor:       BlackColor,
+								Description:     dp.describe(dp.locale().Format(month, dp.locale().MonthLayout), sameMonth(dp.Date, month), disabled),
+								TextColor:       textColor,
 								Size:            14,
 								FontWeight:      font.Bold,
-								BackgroundColor: hoverbg,
+								BackgroundColor: background,
 								CornerRadius:    4,
+								BorderColor:     borderColor,
 							})
 						}))
 					}
@@ -461,29 +1306,42 @@ func (dp *DatePicker) monthGrid(gtx layout.Context, th *material.Theme) layout.D
 	)
 }
 
+// yearGrid draws the cells of the year, decade or century grid.
 func (dp *DatePicker) yearGrid(gtx layout.Context, th *material.Theme) layout.Dimensions {
 	totalSpaceX := gtx.Constraints.Max.X
 	totalSpaceY := gtx.Constraints.Max.Y - 100
+	cells := dp.cells(dp.view)
+	first, span := *dp.firstYear(dp.view), dp.view.span()
 
 	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			var rows []layout.FlexChild
-			for row := 0; row < 5; row++ {
+			for row := 0; row < gridCells/4; row++ {
 				rows = append(rows, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					var yearButtons []layout.FlexChild
+					var cellButtons []layout.FlexChild
 					for col := 0; col < 4; col++ {
 						i := row*4 + col
-						year := dp.YearRange + i
-						yearButtons = append(yearButtons, layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-							if dp.Years[i].Clicked(gtx) {
-								dp.Date = time.Date(year, dp.Date.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
-								dp.ViewMode = "date"
+						start := first + span*i
+						label, size := strconv.Itoa(start), 14
+						if span > 1 {
+							label, size = fmt.Sprintf("%d - %d", start, start+span-1), 12
+						}
+						cellButtons = append(cellButtons, layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							disabled := dp.spanDisabled(start, span)
+							borderColor := util.Transparent
+							if gtx.Focused(dp) && startOf(dp.cursor.Year(), span) == start {
+								borderColor = dp.theme().Focus
 							}
-							if dp.Years[i].Hovered() {
-								hoverbg = MGrayColor
+							textColor := dp.theme().Text
+							background := util.Transparent
+							if disabled {
+								gtx = gtx.Disabled()
+								textColor = dp.theme().Disabled
+								background = dp.theme().DisabledBackground
+							} else if cells[i].Hovered() {
+								background = dp.theme().Hover
 								pointer.CursorPointer.Add(gtx.Ops)
 							} else {
-								hoverbg = Transparent
 								pointer.CursorDefault.Add(gtx.Ops)
 							}
 							gtx.Constraints.Min.X = totalSpaceX / 4
@@ -491,17 +1349,19 @@ func (dp *DatePicker) yearGrid(gtx layout.Context, th *material.Theme) layout.Di
 							gtx.Constraints.Min.Y = totalSpaceY / 5
 							gtx.Constraints.Max.Y = totalSpaceY / 5
 							return util.LayoutButton(gtx, th, util.Button{
-								Text:            strconv.Itoa(year),
-								Button:          &dp.Years[i],
-								TextColor:       BlackColor,
-								Size:            14,
+								Text:            label,
+								Button:          &cells[i],
+								Description:     dp.describe(label, startOf(dp.Date.Year(), span) == start, disabled),
+								TextColor:       textColor,
+								Size:            size,
 								FontWeight:      font.Bold,
-								BackgroundColor: hoverbg,
+								BackgroundColor: background,
 								CornerRadius:    4,
+								BorderColor:     borderColor,
 							})
 						}))
 					}
-					return layout.Flex{Alignment: layout.Middle, Spacing: layout.SpaceEvenly}.Layout(gtx, yearButtons...)
+					return layout.Flex{Alignment: layout.Middle, Spacing: layout.SpaceEvenly}.Layout(gtx, cellButtons...)
 				}))
 			}
 			return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceEvenly}.Layout(gtx, rows...)
@@ -509,21 +1369,100 @@ func (dp *DatePicker) yearGrid(gtx layout.Context, th *material.Theme) layout.Di
 	)
 }
 
-var GreenColor = color.NRGBA{R: 0, G: 255, B: 0, A: 180}
-var GrayColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
-var WhiteColor = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
-var BlackColor = color.NRGBA{R: 0, G: 0, B: 0, A: 255}
-var RedColor = color.NRGBA{R: 255, G: 0, B: 0, A: 255}
-var DarkGrayColor = color.NRGBA{R: 34, G: 40, B: 50, A: 255}
-var Transparent = color.NRGBA{R: 0, G: 0, B: 0, A: 0}
-var DarkRedColor = color.NRGBA{R: 171, G: 36, B: 33, A: 255}
-var LGrayColor = color.NRGBA{R: 238, G: 238, B: 238, A: 255}
-var BlackTextColor = color.NRGBA{R: 28, G: 28, B: 28, A: 255}
-var GrayBackground = color.NRGBA{R: 230, G: 230, B: 235, A: 255}
-var OverlayGray = color.NRGBA{R: 238, G: 238, B: 238, A: 50}
-var GrayTextColor = color.NRGBA{R: 84, G: 100, B: 125, A: 255}
-var GrayShadow_high = color.NRGBA{R: 0, G: 0, B: 0, A: 50}
-var GrayShadow_med = color.NRGBA{R: 0, G: 0, B: 0, A: 30}
-var GrayShadow_low = color.NRGBA{R: 0, G: 0, B: 0, A: 10}
-var MGrayColor = color.NRGBA{R: 220, G: 220, B: 220, A: 255}
-var GrayCardBackground = color.NRGBA{R: 240, G: 240, B: 240, A: 255}
+// timeLayout draws the hour, minute and optional second controls of the
+// selected time of day.
+func (dp *DatePicker) timeLayout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	_, minute, second := dp.Date.Clock()
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return timeField(gtx, th, dp.theme(), dp.shownHour(), &dp.HourDown, &dp.HourUp)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return timeText(gtx, th, dp.theme(), ":")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return timeField(gtx, th, dp.theme(), minute, &dp.MinuteDown, &dp.MinuteUp)
+		}),
+	}
+	if dp.ShowSeconds {
+		children = append(children,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return timeText(gtx, th, dp.theme(), ":")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return timeField(gtx, th, dp.theme(), second, &dp.SecondDown, &dp.SecondUp)
+			}),
+		)
+	}
+	if dp.Use12Hour {
+		children = append(children,
+			layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return util.LayoutButton(gtx, th, util.Button{
+					Text:            dp.Date.Format("PM"),
+					TextColor:       dp.theme().Text,
+					Size:            14,
+					FontWeight:      font.Bold,
+					BackgroundColor: util.Transparent,
+					BorderColor:     dp.theme().Border,
+					CornerRadius:    4,
+					Button:          &dp.AmPmBtn,
+					InInset:         layout.Inset{Left: 10, Right: 10, Top: 5, Bottom: 5},
+				})
+			}),
+		)
+	}
+	return layout.Inset{Left: 10, Right: 10, Top: 5}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+	})
+}
+
+// shownHour returns the hour of Date as the time controls show it, from 1
+// to 12 when Use12Hour is set.
+func (dp *DatePicker) shownHour() int {
+	hour := dp.Date.Hour()
+	if !dp.Use12Hour {
+		return hour
+	}
+	if hour %= 12; hour == 0 {
+		return 12
+	}
+	return hour
+}
+
+// timeField draws one two-digit time value between its decrement and
+// increment buttons.
+func timeField(gtx layout.Context, th *material.Theme, t *Theme, value int, down, up *widget.Clickable) layout.Dimensions {
+	stepButton := func(text string, button *widget.Clickable) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return util.LayoutButton(gtx, th, util.Button{
+				Text:            text,
+				TextColor:       t.Text,
+				Size:            14,
+				FontWeight:      font.Bold,
+				BackgroundColor: util.Transparent,
+				BorderColor:     t.Border,
+				CornerRadius:    4,
+				Button:          button,
+				InInset:         layout.Inset{Left: 8, Right: 8, Top: 2, Bottom: 2},
+			})
+		}
+	}
+	return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(stepButton("-", down)),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return timeText(gtx, th, t, fmt.Sprintf("%02d", value))
+		}),
+		layout.Rigid(stepButton("+", up)),
+	)
+}
+
+func timeText(gtx layout.Context, th *material.Theme, t *Theme, text string) layout.Dimensions {
+	return util.LayoutText(gtx, th, util.Text{
+		Text:       text,
+		Size:       16,
+		TextColor:  t.Text,
+		FontWeight: font.Bold,
+		Inset:      layout.Inset{Left: 5, Right: 5},
+	})
+}
@@ -0,0 +1,1296 @@
+package datepicker
+
+import (
+	"image"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/input"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// frame lays out every picker, one below the other, in a single frame.
+func frame(r *input.Router, th *material.Theme, pickers []*DatePicker) {
+	var ops op.Ops
+	gtx := layout.Context{
+		Ops:         &ops,
+		Constraints: layout.Exact(image.Pt(400, 3000)),
+		Source:      r.Source(),
+		Now:         time.Now(),
+	}
+	children := make([]layout.FlexChild, len(pickers))
+	for i, dp := range pickers {
+		children[i] = layout.Rigid(func(gtx C) D {
+			return dp.Layout(gtx, th)
+		})
+	}
+	layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	r.Frame(&ops)
+}
+
+// drain returns the events Update has queued for dp.
+func drain(r *input.Router, dp *DatePicker) []Event {
+	var ops op.Ops
+	gtx := layout.Context{Ops: &ops, Source: r.Source(), Now: time.Now()}
+	var events []Event
+	for {
+		e, ok := dp.Update(gtx)
+		if !ok {
+			return events
+		}
+		events = append(events, e)
+	}
+}
+
+func TestMultiplePickers(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	var pickers []*DatePicker
+	for i := 0; i < 5; i++ {
+		pickers = append(pickers, &DatePicker{
+			Date:   time.Date(2024, time.Month(i+1), 10, 0, 0, 0, 0, time.UTC),
+			Editor: &widget.Editor{},
+		})
+	}
+	frame(&r, th, pickers)
+
+	// Open the second and fourth picker, and show years in the fourth.
+	pickers[1].Openbtn.Click()
+	pickers[3].Openbtn.Click()
+	frame(&r, th, pickers)
+	pickers[3].SetView(YearView)
+	frame(&r, th, pickers)
+
+	// Pick a day in the second picker.
+	pickers[1].Days[19].Click()
+	frame(&r, th, pickers)
+	frame(&r, th, pickers)
+
+	for i, dp := range pickers {
+		want := time.Date(2024, time.Month(i+1), 10, 0, 0, 0, 0, time.UTC)
+		if i == 1 {
+			want = time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC)
+		}
+		if !dp.Date.Equal(want) {
+			t.Errorf("picker %d: Date = %v, want %v", i, dp.Date, want)
+		}
+		if text, want := dp.Editor.Text(), want.Format(dateLayout); text != want {
+			t.Errorf("picker %d: editor text = %q, want %q", i, text, want)
+		}
+		if open := i == 3; dp.IsOpen != open {
+			t.Errorf("picker %d: IsOpen = %v, want %v", i, dp.IsOpen, open)
+		}
+	}
+	if v := pickers[3].View(); v != YearView {
+		t.Errorf("picker 3: View = %v, want YearView", v)
+	}
+	for _, i := range []int{0, 1, 2, 4} {
+		if v := pickers[i].View(); v != DayView {
+			t.Errorf("picker %d: View = %v, want DayView", i, v)
+		}
+	}
+}
+
+func TestUpdateEvents(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := &DatePicker{
+		Date:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Mode:   DateRange,
+		Editor: &widget.Editor{},
+	}
+	pickers := []*DatePicker{dp}
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	frame(&r, th, pickers)
+
+	steps := []struct {
+		click *widget.Clickable
+		want  []Event
+	}{
+		{&dp.Openbtn, []Event{OpenEvent{}}},
+		{&dp.MonthBtn, []Event{ViewChangeEvent{View: MonthView}}},
+		{&dp.Months[2], []Event{ViewChangeEvent{View: DayView}}},
+		{&dp.Days[9], []Event{SelectEvent{Date: day(10)}}},
+		{&dp.Days[14], []Event{SelectEvent{Date: day(15)}, RangeEvent{Start: day(10), End: day(15)}, CloseEvent{}}},
+		{nil, nil},
+	}
+	for i, step := range steps {
+		if step.click != nil {
+			step.click.Click()
+		}
+		if got := drain(&r, dp); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d: events = %v, want %v", i, got, step.want)
+		}
+		frame(&r, th, pickers)
+	}
+
+	// Layout drops the events that were not asked for.
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+	if got := drain(&r, dp); len(got) != 0 {
+		t.Errorf("events after Layout = %v, want none", got)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := new(DatePicker)
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+	dp.Days[0].Click()
+	frame(&r, th, pickers)
+
+	now := time.Now()
+	want := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+	if text, want := dp.Editor.Text(), want.Format(dateLayout); text != want {
+		t.Errorf("editor text = %q, want %q", text, want)
+	}
+	if day := dp.FirstWeekday; day != time.Monday {
+		t.Errorf("first weekday = %v, want Monday", day)
+	}
+}
+
+func TestPopupOverlay(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker()
+	layoutPicker := func() layout.Dimensions {
+		var ops op.Ops
+		gtx := layout.Context{
+			Ops:         &ops,
+			Constraints: layout.Exact(image.Pt(400, 800)),
+			Source:      r.Source(),
+			Now:         time.Now(),
+		}
+		gtx.Constraints.Min = image.Point{}
+		dims := dp.Layout(gtx, th)
+		r.Frame(&ops)
+		return dims
+	}
+	press := func(x, y float32) {
+		r.Queue(
+			pointer.Event{Kind: pointer.Press, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary, Position: f32.Pt(x, y)},
+			pointer.Event{Kind: pointer.Release, Source: pointer.Mouse, Position: f32.Pt(x, y)},
+		)
+	}
+
+	closed := layoutPicker()
+	dp.Openbtn.Click()
+	layoutPicker()
+	if open := layoutPicker(); !dp.IsOpen || open != closed {
+		t.Fatalf("open picker: IsOpen = %v, dimensions = %v, want true and %v", dp.IsOpen, open, closed)
+	}
+
+	// A press on the popup keeps it open, one outside closes it.
+	press(200, float32(closed.Size.Y+100))
+	layoutPicker()
+	if !dp.IsOpen {
+		t.Error("press inside the popup closed it")
+	}
+	press(200, 790)
+	layoutPicker()
+	if dp.IsOpen {
+		t.Error("press outside the popup left it open")
+	}
+}
+
+func TestPlacePopup(t *testing.T) {
+	input := image.Pt(200, 40)
+	size := image.Pt(300, 400)
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		want   image.Point
+	}{
+		{"no bounds", image.Rectangle{}, image.Pt(0, 50)},
+		{"room below", image.Rect(-10, -10, 800, 600), image.Pt(0, 50)},
+		{"flip above", image.Rect(-10, -500, 800, 300), image.Pt(0, -410)},
+		{"right-align", image.Rect(-200, -10, 250, 600), image.Pt(-100, 50)},
+		{"clamp left", image.Rect(-50, -10, 250, 600), image.Pt(-50, 50)},
+		{"more room below", image.Rect(-10, -100, 800, 300), image.Pt(0, -100)},
+		{"more room above", image.Rect(-10, -300, 800, 200), image.Pt(0, -300)},
+	}
+	for _, tt := range tests {
+		dp := &DatePicker{Bounds: tt.bounds, inputSize: input}
+		if got := dp.placePopup(size, 10); got != tt.want {
+			t.Errorf("%s: placePopup = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInline(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker(WithDate(time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)))
+	dp.Inline = true
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+
+	dp.Days[20].Click()
+	events := drain(&r, dp)
+	want := []Event{SelectEvent{Date: time.Date(2024, time.May, 21, 0, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+
+	// The calendar stays on screen for the next pick.
+	frame(&r, th, pickers)
+	dp.Days[21].Click()
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.May, 22, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+}
+
+func TestAdjacentDays(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker(WithDate(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)), WithFirstWeekday(time.Sunday))
+	dp.ShowAdjacentDays = true
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+
+	// March 2024 starts on a Friday, after five days of February.
+	dp.AdjacentDays[0].Click()
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.February, 25, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+
+	// February 2024 ends on a Thursday, before two days of March.
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+	dp.AdjacentDays[5].Click()
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+}
+
+func TestFixedWeeks(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	// February 2015 takes four rows when weeks start on Sunday, August 2015 six.
+	heights := make(map[bool][]int)
+	for _, fixed := range []bool{false, true} {
+		for _, month := range []time.Month{time.February, time.August} {
+			dp := NewDatePicker(WithDate(time.Date(2015, month, 1, 0, 0, 0, 0, time.UTC)), WithFirstWeekday(time.Sunday))
+			dp.FixedWeeks = fixed
+			dp.IsOpen = true
+			frame(&r, th, []*DatePicker{dp})
+			heights[fixed] = append(heights[fixed], dp.popupRect.Dy())
+		}
+	}
+	if h := heights[false]; h[0] == h[1] {
+		t.Errorf("popup heights without FixedWeeks = %v, want them to differ", h)
+	}
+	if h := heights[true]; h[0] != h[1] {
+		t.Errorf("popup heights with FixedWeeks = %v, want them equal", h)
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+	}
+	// From the last day of each month of a common and a leap year, the day
+	// that "next" and "previous" land on.
+	ends := []struct {
+		year       int
+		month      time.Month
+		next, prev int
+	}{
+		{2023, time.January, 28, 31}, {2024, time.January, 29, 31},
+		{2023, time.February, 28, 28}, {2024, time.February, 29, 29},
+		{2023, time.March, 30, 28}, {2024, time.March, 30, 29},
+		{2023, time.April, 30, 30}, {2024, time.April, 30, 30},
+		{2023, time.May, 30, 30}, {2024, time.May, 30, 30},
+		{2023, time.June, 30, 30}, {2024, time.June, 30, 30},
+		{2023, time.July, 31, 30}, {2024, time.July, 31, 30},
+		{2023, time.August, 30, 31}, {2024, time.August, 30, 31},
+		{2023, time.September, 30, 30}, {2024, time.September, 30, 30},
+		{2023, time.October, 30, 30}, {2024, time.October, 30, 30},
+		{2023, time.November, 30, 30}, {2024, time.November, 30, 30},
+		{2023, time.December, 31, 30}, {2024, time.December, 31, 30},
+	}
+	for _, e := range ends {
+		last := date(e.year, e.month+1, 0)
+		if got, want := addMonths(last, 1), date(e.year, e.month+1, e.next); !got.Equal(want) {
+			t.Errorf("addMonths(%v, 1) = %v, want %v", last, got, want)
+		}
+		if got, want := addMonths(last, -1), date(e.year, e.month-1, e.prev); !got.Equal(want) {
+			t.Errorf("addMonths(%v, -1) = %v, want %v", last, got, want)
+		}
+	}
+
+	tests := []struct {
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{date(2024, time.January, 31), 1, date(2024, time.February, 29)},
+		{date(2023, time.January, 31), 1, date(2023, time.February, 28)},
+		{date(2024, time.March, 31), -1, date(2024, time.February, 29)},
+		{date(2024, time.December, 31), 1, date(2025, time.January, 31)},
+		{date(2025, time.January, 31), -1, date(2024, time.December, 31)},
+		{date(2024, time.August, 31), 6, date(2025, time.February, 28)},
+		{date(2024, time.February, 29), 12, date(2025, time.February, 28)},
+		{date(2024, time.February, 29), -12, date(2023, time.February, 28)},
+		{date(2024, time.February, 29), 48, date(2028, time.February, 29)},
+		{date(1900, time.January, 31), 1, date(1900, time.February, 28)},
+		{date(2000, time.January, 31), 1, date(2000, time.February, 29)},
+		{date(2024, time.March, 15), 0, date(2024, time.March, 15)},
+	}
+	for _, tt := range tests {
+		if got := addMonths(tt.from, tt.n); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%v, %d) = %v, want %v", tt.from, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAddYears(t *testing.T) {
+	tests := []struct {
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), 1, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), -1, time.Date(2023, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), 4, time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1896, time.February, 29, 0, 0, 0, 0, time.UTC), 4, time.Date(1900, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC), 4, time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), 1, time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := addYears(tt.from, tt.n); !got.Equal(tt.want) {
+			t.Errorf("addYears(%v, %d) = %v, want %v", tt.from, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNextMonthFromMonthEnd(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker(WithDate(time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)))
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+	dp.NextBtn.Click()
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC); !dp.page.Equal(want) {
+		t.Errorf("page = %v, want %v", dp.page, want)
+	}
+	if want := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+
+	// The keyboard cursor clamps to the end of the shorter month.
+	dp.PrevBtn.Click()
+	frame(&r, th, pickers)
+	r.Source().Execute(key.FocusCmd{Tag: dp})
+	r.Queue(key.Event{Name: key.NamePageDown, State: key.Press})
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC); !dp.cursor.Equal(want) {
+		t.Errorf("cursor = %v, want %v", dp.cursor, want)
+	}
+}
+
+func TestCenturyView(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker(WithDate(time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)))
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	frame(&r, th, pickers)
+	if len(dp.Years) != 0 || len(dp.Decades) != 0 || len(dp.Centuries) != 0 {
+		t.Fatalf("cells allocated before their grid was shown: %d years, %d decades, %d centuries",
+			len(dp.Years), len(dp.Decades), len(dp.Centuries))
+	}
+
+	// Zoom out from the days to the centuries, one level at a time.
+	for _, want := range []View{YearView, DecadeView, CenturyView} {
+		dp.YearBtn.Click()
+		frame(&r, th, pickers)
+		if v := dp.View(); v != want {
+			t.Fatalf("View = %v, want %v", v, want)
+		}
+	}
+	if dp.CenturyRange != 1000 || len(dp.Centuries) != gridCells {
+		t.Fatalf("CenturyRange = %d with %d cells, want 1000 with %d", dp.CenturyRange, len(dp.Centuries), gridCells)
+	}
+
+	// Then pick 1950 through its century and decade.
+	steps := []struct {
+		click *widget.Clickable
+		view  View
+	}{
+		{&dp.Centuries[9], DecadeView},
+		{&dp.Decades[5], YearView},
+		{&dp.Years[0], MonthView},
+	}
+	for _, step := range steps {
+		step.click.Click()
+		frame(&r, th, pickers)
+		if v := dp.View(); v != step.view {
+			t.Fatalf("View = %v, want %v", v, step.view)
+		}
+	}
+	if want := time.Date(1950, time.May, 1, 0, 0, 0, 0, time.UTC); !dp.page.Equal(want) {
+		t.Errorf("page = %v, want %v", dp.page, want)
+	}
+	if want := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", dp.Date, want)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	dp := NewDatePicker(WithDate(day(5)))
+	dp.Mode = DateRange
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	drain(&r, dp)
+	frame(&r, th, pickers)
+
+	// inRange reports, for each day from the 8th to the 21st, whether it is
+	// drawn as part of the range.
+	shown := func() []int {
+		var days []int
+		for d := 8; d <= 21; d++ {
+			if dp.inRange(day(d)) {
+				days = append(days, d)
+			}
+		}
+		return days
+	}
+	steps := []struct {
+		name  string
+		click *widget.Clickable
+		want  []Event
+		start time.Time
+		end   time.Time
+		open  bool
+		// hover is the day under the pointer and shown the days drawn in
+		// the range while it is there.
+		hover time.Time
+		shown []int
+	}{
+		{
+			name: "first click starts the range", click: &dp.Days[11],
+			want:  []Event{SelectEvent{Date: day(12)}},
+			start: day(12), open: true,
+			hover: day(15), shown: []int{12, 13, 14, 15},
+		},
+		{
+			name:  "preview before the start",
+			start: day(12), open: true,
+			hover: day(9), shown: []int{9, 10, 11, 12},
+		},
+		{
+			name:  "no preview without a hover",
+			start: day(12), open: true,
+		},
+		{
+			name: "second click before the start swaps the ends", click: &dp.Days[8],
+			want:  []Event{SelectEvent{Date: day(9)}, RangeEvent{Start: day(9), End: day(12)}, CloseEvent{}},
+			start: day(9), end: day(12),
+			hover: day(20), shown: []int{9, 10, 11, 12},
+		},
+		{
+			name: "reopen", click: &dp.Openbtn,
+			want:  []Event{OpenEvent{}},
+			start: day(9), end: day(12), open: true,
+			shown: []int{9, 10, 11, 12},
+		},
+		{
+			name: "third click starts a new range", click: &dp.Days[19],
+			want:  []Event{SelectEvent{Date: day(20)}},
+			start: day(20), open: true,
+			hover: day(21), shown: []int{20, 21},
+		},
+		{
+			name: "second click after the start ends it", click: &dp.Days[20],
+			want:  []Event{SelectEvent{Date: day(21)}, RangeEvent{Start: day(20), End: day(21)}, CloseEvent{}},
+			start: day(20), end: day(21),
+			shown: []int{20, 21},
+		},
+	}
+	for _, step := range steps {
+		if step.click != nil {
+			step.click.Click()
+		}
+		if got := drain(&r, dp); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: events = %v, want %v", step.name, got, step.want)
+		}
+		if !dp.RangeStart.Equal(step.start) || !dp.RangeEnd.Equal(step.end) || dp.IsOpen != step.open {
+			t.Errorf("%s: range = %v to %v, open %v, want %v to %v, open %v",
+				step.name, dp.RangeStart, dp.RangeEnd, dp.IsOpen, step.start, step.end, step.open)
+		}
+		dp.hoverDate = step.hover
+		if got := shown(); !slices.Equal(got, step.shown) {
+			t.Errorf("%s: days in range = %v, want %v", step.name, got, step.shown)
+		}
+		frame(&r, th, pickers)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	dp := NewDatePicker(WithDate(day(time.March, 15)), WithBounds(day(time.March, 10), day(time.April, 20)))
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	drain(&r, dp)
+	frame(&r, th, pickers)
+
+	// Disabled cells ignore clicks, and paging stops at the last page with
+	// something to pick.
+	type step struct {
+		name  string
+		click *widget.Clickable
+		view  View
+		years int // YearRange, checked in the year grid only
+		want  []Event
+	}
+	run := func(steps []step) {
+		for _, step := range steps {
+			step.click.Click()
+			if got := drain(&r, dp); !reflect.DeepEqual(got, step.want) {
+				t.Errorf("%s: events = %v, want %v", step.name, got, step.want)
+			}
+			frame(&r, th, pickers)
+			if v := dp.View(); v != step.view {
+				t.Errorf("%s: View = %v, want %v", step.name, v, step.view)
+			}
+			if step.view == YearView && dp.YearRange != step.years {
+				t.Errorf("%s: YearRange = %d, want %d", step.name, dp.YearRange, step.years)
+			}
+			if !dp.Date.Equal(day(time.March, 15)) {
+				t.Errorf("%s: Date = %v, want it unchanged", step.name, dp.Date)
+			}
+		}
+	}
+	run([]step{
+		{name: "day before MinDate", click: &dp.Days[8], view: DayView},
+		{name: "prev from the first month", click: &dp.PrevBtn, view: DayView},
+	})
+	if want := day(time.March, 1); !dp.page.Equal(want) {
+		t.Errorf("page = %v, want %v", dp.page, want)
+	}
+	run([]step{
+		{name: "next", click: &dp.NextBtn, view: DayView},
+		{name: "next from the last month", click: &dp.NextBtn, view: DayView},
+		{name: "day after MaxDate", click: &dp.Days[20], view: DayView},
+		{name: "month grid", click: &dp.MonthBtn, view: MonthView, want: []Event{ViewChangeEvent{View: MonthView}}},
+		{name: "month before MinDate", click: &dp.Months[1], view: MonthView},
+		{name: "month after MaxDate", click: &dp.Months[4], view: MonthView},
+		{name: "prev from the only year", click: &dp.PrevBtn, view: MonthView},
+		{name: "next from the only year", click: &dp.NextBtn, view: MonthView},
+		{name: "year grid", click: &dp.YearBtn, view: YearView, years: 2014, want: []Event{ViewChangeEvent{View: YearView}}},
+	})
+	run([]step{
+		{name: "year before MinDate", click: &dp.Years[9], view: YearView, years: 2014},
+		{name: "year after MaxDate", click: &dp.Years[11], view: YearView, years: 2014},
+		{name: "prev from the only years", click: &dp.PrevBtn, view: YearView, years: 2014},
+		{name: "next from the only years", click: &dp.NextBtn, view: YearView, years: 2014},
+	})
+	if want := day(time.April, 1); !dp.page.Equal(want) {
+		t.Errorf("page = %v, want %v", dp.page, want)
+	}
+
+	// Go to Today lands on the nearest bound when today is outside them.
+	future := NewDatePicker(WithDate(time.Date(2100, time.June, 15, 0, 0, 0, 0, time.Local)), WithBounds(
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2100, time.December, 31, 0, 0, 0, 0, time.Local),
+	))
+	pickers = []*DatePicker{dp, future}
+	frame(&r, th, pickers)
+	tests := []struct {
+		dp   *DatePicker
+		want time.Time
+	}{
+		{dp, day(time.April, 20)},
+		{future, time.Date(2100, time.January, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		tt.dp.TodayBtn.Click()
+		if got, want := drain(&r, tt.dp), []Event{SelectEvent{Date: tt.want}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("today: events = %v, want %v", got, want)
+		}
+		if !tt.dp.Date.Equal(tt.want) || !tt.dp.page.Equal(monthOf(tt.want)) {
+			t.Errorf("today: Date = %v, page = %v, want %v", tt.dp.Date, tt.dp.page, tt.want)
+		}
+	}
+}
+
+func TestTodayCellSize(t *testing.T) {
+	th := material.NewTheme()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dp := NewDatePicker(WithDate(today))
+	size := image.Pt(50, 60)
+	for _, day := range []time.Time{today, today.AddDate(0, 0, 1)} {
+		var r input.Router
+		button := new(widget.Clickable)
+		var ops op.Ops
+		gtx := layout.Context{Ops: &ops, Constraints: layout.Exact(size), Source: r.Source(), Now: now}
+		if dims := dp.dayCell(gtx, th, day, button); dims.Size != size {
+			t.Errorf("%s: cell size = %v, want %v", day.Format(dateLayout), dims.Size, size)
+		}
+		r.Frame(&ops)
+
+		// The button takes clicks up to the corners of the cell.
+		for _, pos := range []f32.Point{f32.Pt(2, 2), f32.Pt(47, 57)} {
+			r.Queue(
+				pointer.Event{Kind: pointer.Press, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary, Position: pos},
+				pointer.Event{Kind: pointer.Release, Source: pointer.Mouse, Position: pos},
+			)
+			gtx := layout.Context{Ops: new(op.Ops), Source: r.Source(), Now: now}
+			if !button.Clicked(gtx) {
+				t.Errorf("%s: press at %v missed the button", day.Format(dateLayout), pos)
+			}
+		}
+	}
+}
+
+func TestPagingSendsNoSelectEvent(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	dp := NewDatePicker(WithDate(day(time.March, 10)))
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	dp.Openbtn.Click()
+	if got, want := drain(&r, dp), []Event{OpenEvent{}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("open: events = %v, want %v", got, want)
+	}
+	frame(&r, th, pickers)
+
+	// Paging moves the shown month and leaves Date alone; picking the day
+	// that is already selected changes nothing either.
+	steps := []struct {
+		name  string
+		click *widget.Clickable
+		key   key.Name
+		page  time.Time
+		date  time.Time
+		want  []Event
+	}{
+		{name: "next", click: &dp.NextBtn, page: day(time.April, 1), date: day(time.March, 10)},
+		{name: "prev", click: &dp.PrevBtn, page: day(time.March, 1), date: day(time.March, 10)},
+		{name: "page down", key: key.NamePageDown, page: day(time.April, 1), date: day(time.March, 10)},
+		{name: "page up", key: key.NamePageUp, page: day(time.March, 1), date: day(time.March, 10)},
+		{name: "up", key: key.NameUpArrow, page: day(time.March, 1), date: day(time.March, 10)},
+		{name: "up into February", key: key.NameUpArrow, page: day(time.February, 1), date: day(time.March, 10)},
+		{name: "month grid", click: &dp.MonthBtn, page: day(time.February, 1), date: day(time.March, 10), want: []Event{ViewChangeEvent{View: MonthView}}},
+		{name: "pick a month", click: &dp.Months[5], page: day(time.June, 1), date: day(time.March, 10), want: []Event{ViewChangeEvent{View: DayView}}},
+		{name: "pick a day", click: &dp.Days[1], page: day(time.June, 1), date: day(time.June, 2), want: []Event{SelectEvent{Date: day(time.June, 2)}, CloseEvent{}}},
+		{name: "reopen", click: &dp.Openbtn, page: day(time.June, 1), date: day(time.June, 2), want: []Event{OpenEvent{}}},
+		{name: "pick the same day", click: &dp.Days[1], page: day(time.June, 1), date: day(time.June, 2), want: []Event{CloseEvent{}}},
+	}
+	for _, step := range steps {
+		if step.click != nil {
+			step.click.Click()
+		} else {
+			r.Queue(key.Event{Name: step.key, State: key.Press})
+		}
+		if got := drain(&r, dp); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: events = %v, want %v", step.name, got, step.want)
+		}
+		if !dp.page.Equal(step.page) || !dp.Date.Equal(step.date) {
+			t.Errorf("%s: page = %v, Date = %v, want %v and %v", step.name, dp.page, dp.Date, step.page, step.date)
+		}
+		if text, want := dp.Editor.Text(), step.date.Format(dateLayout); text != want {
+			t.Errorf("%s: editor text = %q, want %q", step.name, text, want)
+		}
+		frame(&r, th, pickers)
+	}
+
+	// Submitting the text that is already there changes nothing.
+	r.Source().Execute(key.FocusCmd{Tag: dp.Editor})
+	frame(&r, th, pickers)
+	r.Queue(key.Event{Name: key.NameReturn, State: key.Press})
+	if got := drain(&r, dp); len(got) != 0 {
+		t.Errorf("submit unchanged text: events = %v, want none", got)
+	}
+}
+
+func TestWeekNumbers(t *testing.T) {
+	th := material.NewTheme()
+	day := func(d int) time.Time {
+		return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC)
+	}
+	// With weeks starting on Sunday, the second row of October 2024 runs
+	// from Sunday 6 to Saturday 12, and is labelled with ISO week 41, which
+	// runs from Monday 7 to Sunday 13.
+	if _, week := day(10).ISOWeek(); week != 41 {
+		t.Fatalf("ISO week of 10 October 2024 = %d, want 41", week)
+	}
+	for _, mode := range []SelectionMode{DateRange, MultiDate} {
+		var r input.Router
+		dp := NewDatePicker(WithDate(day(1)), WithFirstWeekday(time.Sunday))
+		dp.Mode = mode
+		dp.ShowWeekNumbers = true
+		pickers := []*DatePicker{dp}
+		frame(&r, th, pickers)
+		dp.Openbtn.Click()
+		frame(&r, th, pickers)
+		dp.WeekBtns[1].Click()
+		frame(&r, th, pickers)
+
+		switch mode {
+		case DateRange:
+			if !dp.RangeStart.Equal(day(7)) || !dp.RangeEnd.Equal(day(13)) {
+				t.Errorf("DateRange: range = %v to %v, want %v to %v", dp.RangeStart, dp.RangeEnd, day(7), day(13))
+			}
+		case MultiDate:
+			var want []time.Time
+			for d := 7; d <= 13; d++ {
+				want = append(want, day(d))
+			}
+			if !reflect.DeepEqual(dp.Selected, want) {
+				t.Errorf("MultiDate: Selected = %v, want %v", dp.Selected, want)
+			}
+		}
+	}
+}
+
+func TestLocaleRoundTrip(t *testing.T) {
+	locales := []struct {
+		name   string
+		locale *Locale
+	}{
+		{"English", &English},
+		{"French", &French},
+		{"German", &German},
+		{"Japanese", &Japanese},
+		{"Arabic", &Arabic},
+	}
+	for _, l := range locales {
+		layouts := []string{
+			"02 Jan 2006",
+			"2 January 2006",
+			"Mon 02 Jan 2006",
+			"Monday, 2 January 2006 15:04",
+			l.locale.DateLayout,
+		}
+		for _, layout := range layouts {
+			for month := time.January; month <= time.December; month++ {
+				date := time.Date(2024, month, 10+int(month), 0, 0, 0, 0, time.UTC)
+				text := l.locale.Format(date, layout)
+				got, err := l.locale.Parse(layout, text, time.UTC)
+				if err != nil {
+					t.Errorf("%s: Parse(%q, %q): %v", l.name, layout, text, err)
+					continue
+				}
+				if !got.Equal(date) {
+					t.Errorf("%s: Parse(%q, %q) = %v, want %v", l.name, layout, text, got, date)
+				}
+			}
+		}
+	}
+}
+
+func TestLocaleParse(t *testing.T) {
+	tests := []struct {
+		locale *Locale
+		layout string
+		value  string
+		want   time.Time
+	}{
+		{&French, "2 Jan 2006", "5 janv. 2024", time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{&French, "2 Jan 2006", "5 JANV. 2024", time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{&French, "2 January 2006", "5 janvier 2024", time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		// "mars" is March, and only a weekday when the layout has one.
+		{&French, "2 Jan 2006", "5 mars 2024", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{&French, "Mon 2 Jan 2006", "mar. 5 mars 2024", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{&German, "2. January 2006", "5. März 2024", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{&German, "2. January 2006", "5. märz 2024", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		// "10月" must not be read as "1月" followed by a stray "0".
+		{&Japanese, "2006年January2日", "2024年10月5日", time.Date(2024, time.October, 5, 0, 0, 0, 0, time.UTC)},
+		{&Japanese, "2006年January2日", "2024年1月5日", time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{&Japanese, "2006年January2日 Monday", "2024年11月5日 火曜日", time.Date(2024, time.November, 5, 0, 0, 0, 0, time.UTC)},
+		{&Arabic, "Monday، 2 January 2006", "الثلاثاء، 5 مارس 2024", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := tt.locale.Parse(tt.layout, tt.value, time.UTC)
+		if err != nil {
+			t.Errorf("Parse(%q, %q): %v", tt.layout, tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q, %q) = %v, want %v", tt.layout, tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"5 foo 2024", "", "2024-03-05"} {
+		if got, err := French.Parse("2 Jan 2006", value, time.UTC); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestParseDateRejections(t *testing.T) {
+	dp := NewDatePicker(
+		WithDate(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)),
+		WithBounds(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Time{}),
+	)
+	dp.IsDisabled = func(day time.Time) bool { return day.Weekday() == time.Sunday }
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"31-Dec-2023", `date 31-Dec-2023 is outside the allowed range`},
+		{"10-Mar-2024", `date 10-Mar-2024 is not available`},
+		{"soon", `invalid date "soon", expected a date like 05-Mar-2024`},
+	}
+	for _, tt := range tests {
+		_, err := dp.parseDate(tt.text)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("parseDate(%q) error = %v, want %q", tt.text, err, tt.want)
+		}
+	}
+}
+
+func TestCommitText(t *testing.T) {
+	date := func(d, hour, minute int) time.Time {
+		return time.Date(2024, time.March, d, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		setup    func(dp *DatePicker)
+		text     string
+		err      bool
+		date     time.Time
+		rng      [2]time.Time
+		selected []time.Time
+		editor   string
+	}{
+		{
+			name:   "default layout",
+			text:   "10-Mar-2024",
+			date:   date(10, 0, 0),
+			editor: "10-Mar-2024",
+		},
+		{
+			name:   "invalid text",
+			text:   "10 Marchember",
+			err:    true,
+			date:   date(5, 14, 30),
+			editor: "10 Marchember",
+		},
+		{
+			name:   "input format fallback",
+			setup:  func(dp *DatePicker) { dp.Format, dp.InputFormats = DayMonthYear, []string{ISODate} },
+			text:   "2024-03-10",
+			date:   date(10, 0, 0),
+			editor: "10/03/2024",
+		},
+		{
+			name:   "format without fallback",
+			setup:  func(dp *DatePicker) { dp.Format = DayMonthYear },
+			text:   "2024-03-10",
+			err:    true,
+			date:   date(5, 14, 30),
+			editor: "2024-03-10",
+		},
+		{
+			name:   "date and time",
+			setup:  func(dp *DatePicker) { dp.ShowTime = true },
+			text:   "10-Mar-2024 09:15",
+			date:   date(10, 9, 15),
+			editor: "10-Mar-2024 09:15",
+		},
+		{
+			name:   "date only keeps the time",
+			setup:  func(dp *DatePicker) { dp.ShowTime = true },
+			text:   "10-Mar-2024",
+			date:   date(10, 14, 30),
+			editor: "10-Mar-2024 14:30",
+		},
+		{
+			name:   "range",
+			setup:  func(dp *DatePicker) { dp.Mode = DateRange },
+			text:   "12-Mar-2024 - 10-Mar-2024",
+			date:   date(10, 0, 0),
+			rng:    [2]time.Time{date(10, 0, 0), date(12, 0, 0)},
+			editor: "10-Mar-2024 - 12-Mar-2024",
+		},
+		{
+			name:   "range without separator",
+			setup:  func(dp *DatePicker) { dp.Mode = DateRange },
+			text:   "10-Mar-2024",
+			err:    true,
+			date:   date(5, 14, 30),
+			editor: "10-Mar-2024",
+		},
+		{
+			name:     "multiple dates",
+			setup:    func(dp *DatePicker) { dp.Mode = MultiDate },
+			text:     "12-Mar-2024, 10-Mar-2024, 12-Mar-2024,",
+			date:     date(5, 14, 30),
+			selected: []time.Time{date(10, 0, 0), date(12, 0, 0)},
+			editor:   "10-Mar-2024, 12-Mar-2024",
+		},
+		{
+			name:   "multiple dates with an invalid one",
+			setup:  func(dp *DatePicker) { dp.Mode = MultiDate },
+			text:   "10-Mar-2024, never",
+			err:    true,
+			date:   date(5, 14, 30),
+			editor: "10-Mar-2024, never",
+		},
+		{
+			name:     "multiple dates with a comma in the format",
+			setup:    func(dp *DatePicker) { dp.Mode, dp.Format = MultiDate, "Jan 2, 2006" },
+			text:     "Mar 12, 2024; Mar 10, 2024",
+			date:     date(5, 14, 30),
+			selected: []time.Time{date(10, 0, 0), date(12, 0, 0)},
+			editor:   "Mar 10, 2024; Mar 12, 2024",
+		},
+		{
+			name:   "range with a dash in the format",
+			setup:  func(dp *DatePicker) { dp.Mode, dp.Format = DateRange, "2006 - 01 - 02" },
+			text:   "2024 - 03 - 12 – 2024 - 03 - 10",
+			date:   date(10, 0, 0),
+			rng:    [2]time.Time{date(10, 0, 0), date(12, 0, 0)},
+			editor: "2024 - 03 - 10 – 2024 - 03 - 12",
+		},
+		{
+			name:   "range with a dash in an input format",
+			setup:  func(dp *DatePicker) { dp.Mode, dp.InputFormats = DateRange, []string{"2006 - 01 - 02"} },
+			text:   "2024 - 03 - 12 – 10-Mar-2024",
+			date:   date(10, 0, 0),
+			rng:    [2]time.Time{date(10, 0, 0), date(12, 0, 0)},
+			editor: "10-Mar-2024 – 12-Mar-2024",
+		},
+	}
+	for _, tt := range tests {
+		dp := NewDatePicker(WithDate(date(5, 14, 30)))
+		if tt.setup != nil {
+			tt.setup(dp)
+		}
+		dp.Editor.SetText(tt.text)
+		dp.commitText(tt.text)
+		if err := dp.ParseErr; (err != nil) != tt.err {
+			t.Errorf("%s: ParseErr = %v, want error %v", tt.name, err, tt.err)
+		}
+		if !dp.Date.Equal(tt.date) {
+			t.Errorf("%s: Date = %v, want %v", tt.name, dp.Date, tt.date)
+		}
+		if !dp.RangeStart.Equal(tt.rng[0]) || !dp.RangeEnd.Equal(tt.rng[1]) {
+			t.Errorf("%s: range = %v to %v, want %v to %v", tt.name, dp.RangeStart, dp.RangeEnd, tt.rng[0], tt.rng[1])
+		}
+		if !slices.EqualFunc(dp.Selected, tt.selected, time.Time.Equal) {
+			t.Errorf("%s: Selected = %v, want %v", tt.name, dp.Selected, tt.selected)
+		}
+		if text := dp.Editor.Text(); text != tt.editor {
+			t.Errorf("%s: editor text = %q, want %q", tt.name, text, tt.editor)
+		}
+	}
+}
+
+func TestEditorCommit(t *testing.T) {
+	th := material.NewTheme()
+	var r input.Router
+	dp := NewDatePicker(WithDate(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)))
+	pickers := []*DatePicker{dp}
+	frame(&r, th, pickers)
+	r.Source().Execute(key.FocusCmd{Tag: dp.Editor})
+	frame(&r, th, pickers)
+
+	// Typing alone changes nothing until Enter is pressed.
+	dp.Editor.SetText("10-Mar-2024")
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("before submit: Date = %v, want %v", dp.Date, want)
+	}
+	r.Queue(key.Event{Name: key.NameReturn, State: key.Press})
+	if got, want := drain(&r, dp), []Event{SelectEvent{Date: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("submit: events = %v, want %v", got, want)
+	}
+	frame(&r, th, pickers)
+
+	// Moving the focus away commits the text as well.
+	dp.Editor.SetText("12-Mar-2024")
+	frame(&r, th, pickers)
+	r.Source().Execute(key.FocusCmd{})
+	frame(&r, th, pickers)
+	if want := time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC); !dp.Date.Equal(want) {
+		t.Errorf("after blur: Date = %v, want %v", dp.Date, want)
+	}
+
+	// Invalid text stays in the editor when it loses the focus.
+	r.Source().Execute(key.FocusCmd{Tag: dp.Editor})
+	frame(&r, th, pickers)
+	dp.Editor.SetText("12-Foo-2024")
+	frame(&r, th, pickers)
+	r.Source().Execute(key.FocusCmd{})
+	frame(&r, th, pickers)
+	if dp.ParseErr == nil {
+		t.Error("invalid text on blur: ParseErr = nil")
+	}
+	if text := dp.Editor.Text(); text != "12-Foo-2024" {
+		t.Errorf("invalid text on blur: editor text = %q, want %q", text, "12-Foo-2024")
+	}
+}
+
+func TestTimeControls(t *testing.T) {
+	th := material.NewTheme()
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, second, 0, time.UTC)
+	}
+	type step struct {
+		click  func(dp *DatePicker) *widget.Clickable
+		date   time.Time
+		hour   int // as shown by the hour control
+		editor string
+	}
+	tests := []struct {
+		name  string
+		setup func(dp *DatePicker)
+		steps []step
+	}{
+		{
+			name:  "24-hour fields wrap without carrying",
+			setup: func(dp *DatePicker) { dp.ShowSeconds = true },
+			steps: []step{
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourUp }, at(10, 0, 59, 59), 0, "10-Mar-2024 00:59:59"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourDown }, at(10, 23, 59, 59), 23, "10-Mar-2024 23:59:59"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.MinuteUp }, at(10, 23, 0, 59), 23, "10-Mar-2024 23:00:59"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.MinuteDown }, at(10, 23, 59, 59), 23, "10-Mar-2024 23:59:59"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.SecondUp }, at(10, 23, 59, 0), 23, "10-Mar-2024 23:59:00"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.SecondDown }, at(10, 23, 59, 59), 23, "10-Mar-2024 23:59:59"},
+			},
+		},
+		{
+			name:  "12-hour display and AM/PM",
+			setup: func(dp *DatePicker) { dp.Use12Hour = true },
+			steps: []step{
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourUp }, at(10, 0, 59, 59), 12, "10-Mar-2024 12:59 AM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.AmPmBtn }, at(10, 12, 59, 59), 12, "10-Mar-2024 12:59 PM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourUp }, at(10, 13, 59, 59), 1, "10-Mar-2024 01:59 PM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.AmPmBtn }, at(10, 1, 59, 59), 1, "10-Mar-2024 01:59 AM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourDown }, at(10, 0, 59, 59), 12, "10-Mar-2024 12:59 AM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.HourDown }, at(10, 23, 59, 59), 11, "10-Mar-2024 11:59 PM"},
+				{func(dp *DatePicker) *widget.Clickable { return &dp.AmPmBtn }, at(10, 11, 59, 59), 11, "10-Mar-2024 11:59 AM"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var r input.Router
+		dp := NewDatePicker(WithDate(at(10, 23, 59, 59)))
+		dp.ShowTime = true
+		tt.setup(dp)
+		pickers := []*DatePicker{dp}
+		frame(&r, th, pickers)
+		dp.Openbtn.Click()
+		drain(&r, dp)
+		frame(&r, th, pickers)
+		for i, step := range tt.steps {
+			step.click(dp).Click()
+			if got, want := drain(&r, dp), []Event{SelectEvent{Date: step.date}}; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s, step %d: events = %v, want %v", tt.name, i, got, want)
+			}
+			frame(&r, th, pickers)
+			if !dp.Date.Equal(step.date) {
+				t.Errorf("%s, step %d: Date = %v, want %v", tt.name, i, dp.Date, step.date)
+			}
+			if hour := dp.shownHour(); hour != step.hour {
+				t.Errorf("%s, step %d: shown hour = %d, want %d", tt.name, i, hour, step.hour)
+			}
+			if text := dp.Editor.Text(); text != step.editor {
+				t.Errorf("%s, step %d: editor text = %q, want %q", tt.name, i, text, step.editor)
+			}
+		}
+	}
+
+	// The time of day applies to the whole selection in the other modes.
+	var r input.Router
+	rangePicker := NewDatePicker(WithDate(at(12, 14, 30, 0)))
+	rangePicker.ShowTime, rangePicker.Mode = true, DateRange
+	rangePicker.RangeStart, rangePicker.RangeEnd = at(10, 14, 30, 0), at(12, 14, 30, 0)
+	multiPicker := NewDatePicker(WithDate(at(12, 14, 30, 0)))
+	multiPicker.ShowTime, multiPicker.Mode = true, MultiDate
+	multiPicker.Selected = []time.Time{at(10, 14, 30, 0), at(12, 14, 30, 0)}
+	pickers := []*DatePicker{rangePicker, multiPicker}
+	frame(&r, th, pickers)
+
+	rangePicker.HourUp.Click()
+	want := []Event{SelectEvent{Date: at(12, 15, 30, 0)}, RangeEvent{Start: at(10, 15, 30, 0), End: at(12, 15, 30, 0)}}
+	if got := drain(&r, rangePicker); !reflect.DeepEqual(got, want) {
+		t.Errorf("range: events = %v, want %v", got, want)
+	}
+	multiPicker.MinuteUp.Click()
+	if got, want := drain(&r, multiPicker), []Event{SelectEvent{Date: at(12, 14, 31, 0)}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("multiple dates: events = %v, want %v", got, want)
+	}
+	if want := []time.Time{at(10, 14, 31, 0), at(12, 14, 31, 0)}; !slices.EqualFunc(multiPicker.Selected, want, time.Time.Equal) {
+		t.Errorf("multiple dates: Selected = %v, want %v", multiPicker.Selected, want)
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	th := material.NewTheme()
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	type step struct {
+		key    key.Name
+		mods   key.Modifiers
+		cursor time.Time // zero to skip the check
+		page   time.Time
+		view   View
+		open   bool
+	}
+	// Every grid starts out on Wednesday 13 March 2024, with weeks starting
+	// on Monday. Only Enter on a day changes Date.
+	grids := []struct {
+		view  View
+		steps []step
+		date  time.Time
+	}{
+		{DayView, []step{
+			{key: key.NameRightArrow, cursor: date(2024, time.March, 14), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameDownArrow, cursor: date(2024, time.March, 21), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameHome, cursor: date(2024, time.March, 18), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameEnd, cursor: date(2024, time.March, 24), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameUpArrow, cursor: date(2024, time.March, 17), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameLeftArrow, cursor: date(2024, time.March, 16), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NamePageDown, cursor: date(2024, time.April, 16), page: date(2024, time.April, 1), view: DayView, open: true},
+			{key: key.NamePageDown, mods: key.ModShift, cursor: date(2025, time.April, 16), page: date(2025, time.April, 1), view: DayView, open: true},
+			{key: key.NamePageUp, mods: key.ModShift, cursor: date(2024, time.April, 16), page: date(2024, time.April, 1), view: DayView, open: true},
+			{key: key.NamePageUp, cursor: date(2024, time.March, 16), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameReturn, cursor: date(2024, time.March, 16), page: date(2024, time.March, 1), view: DayView, open: false},
+		}, date(2024, time.March, 16)},
+		{MonthView, []step{
+			{key: key.NameRightArrow, cursor: date(2024, time.April, 1), page: date(2024, time.March, 1), view: MonthView, open: true},
+			{key: key.NameDownArrow, cursor: date(2024, time.August, 1), page: date(2024, time.March, 1), view: MonthView, open: true},
+			{key: key.NameHome, cursor: date(2024, time.May, 1), page: date(2024, time.March, 1), view: MonthView, open: true},
+			{key: key.NameEnd, cursor: date(2024, time.August, 1), page: date(2024, time.March, 1), view: MonthView, open: true},
+			{key: key.NamePageDown, cursor: date(2025, time.August, 1), page: date(2025, time.March, 1), view: MonthView, open: true},
+			{key: key.NameUpArrow, cursor: date(2025, time.April, 1), page: date(2025, time.March, 1), view: MonthView, open: true},
+			{key: key.NameLeftArrow, cursor: date(2025, time.March, 1), page: date(2025, time.March, 1), view: MonthView, open: true},
+			{key: key.NamePageUp, cursor: date(2024, time.March, 1), page: date(2024, time.March, 1), view: MonthView, open: true},
+			{key: key.NameEnter, cursor: date(2024, time.March, 1), page: date(2024, time.March, 1), view: DayView, open: true},
+			{key: key.NameEscape, page: date(2024, time.March, 1), view: DayView, open: false},
+		}, date(2024, time.March, 13)},
+		{YearView, []step{
+			{key: key.NameRightArrow, cursor: date(2025, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameDownArrow, cursor: date(2029, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameHome, cursor: date(2026, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameEnd, cursor: date(2029, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NamePageDown, cursor: date(2049, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameUpArrow, cursor: date(2045, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameReturn, cursor: date(2045, time.March, 1), page: date(2045, time.March, 1), view: MonthView, open: true},
+			{key: key.NameEscape, page: date(2045, time.March, 1), view: DayView, open: true},
+		}, date(2024, time.March, 13)},
+		{DecadeView, []step{
+			{key: key.NameLeftArrow, cursor: date(2010, time.March, 1), page: date(2024, time.March, 1), view: DecadeView, open: true},
+			{key: key.NameUpArrow, cursor: date(1970, time.March, 1), page: date(2024, time.March, 1), view: DecadeView, open: true},
+			{key: key.NameReturn, cursor: date(1970, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameRightArrow, cursor: date(1971, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+			{key: key.NameEscape, page: date(2024, time.March, 1), view: MonthView, open: true},
+		}, date(2024, time.March, 13)},
+		{CenturyView, []step{
+			{key: key.NameLeftArrow, cursor: date(1900, time.March, 1), page: date(2024, time.March, 1), view: CenturyView, open: true},
+			{key: key.NameEscape, cursor: date(1900, time.March, 1), page: date(2024, time.March, 1), view: DecadeView, open: true},
+			{key: key.NameRightArrow, cursor: date(1910, time.March, 1), page: date(2024, time.March, 1), view: DecadeView, open: true},
+			{key: key.NameEnter, cursor: date(1910, time.March, 1), page: date(2024, time.March, 1), view: YearView, open: true},
+		}, date(2024, time.March, 13)},
+	}
+	for _, grid := range grids {
+		var r input.Router
+		dp := NewDatePicker(WithDate(date(2024, time.March, 13)))
+		pickers := []*DatePicker{dp}
+		frame(&r, th, pickers)
+		dp.Openbtn.Click()
+		frame(&r, th, pickers)
+		dp.SetView(grid.view)
+		frame(&r, th, pickers)
+		for i, s := range grid.steps {
+			r.Queue(key.Event{Name: s.key, Modifiers: s.mods, State: key.Press})
+			frame(&r, th, pickers)
+			if !s.cursor.IsZero() && !dp.cursor.Equal(s.cursor) {
+				t.Errorf("%v step %d (%s): cursor = %v, want %v", grid.view, i, s.key, dp.cursor, s.cursor)
+			}
+			if !dp.page.Equal(s.page) {
+				t.Errorf("%v step %d (%s): page = %v, want %v", grid.view, i, s.key, dp.page, s.page)
+			}
+			if dp.View() != s.view || dp.IsOpen != s.open {
+				t.Errorf("%v step %d (%s): view = %v, open = %v, want %v and %v", grid.view, i, s.key, dp.View(), dp.IsOpen, s.view, s.open)
+			}
+		}
+		if !dp.Date.Equal(grid.date) {
+			t.Errorf("%v: Date = %v, want %v", grid.view, dp.Date, grid.date)
+		}
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	date := time.Date(2024, time.March, 10, 8, 30, 0, 0, time.UTC)
+	want := time.Date(2024, time.March, 10, 8, 30, 0, 0, loc)
+	for _, opts := range [][]Option{
+		{WithLocation(loc), WithDate(date)},
+		{WithDate(date), WithLocation(loc)},
+	} {
+		if got := NewDatePicker(opts...).Date; !got.Equal(want) || got.Location() != loc {
+			t.Errorf("Date = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSpanGridsSkipIsDisabled(t *testing.T) {
+	th := material.NewTheme()
+	now := time.Now()
+	for _, view := range []View{DecadeView, CenturyView} {
+		var r input.Router
+		dp := NewDatePicker()
+		calls := 0
+		dp.IsDisabled = func(day time.Time) bool {
+			calls++
+			return day.Before(now)
+		}
+		pickers := []*DatePicker{dp}
+		dp.IsOpen = true
+		dp.SetView(view)
+		calls = 0
+		frame(&r, th, pickers)
+		// Drawing the grid must not ask about every day of its cells.
+		if calls > 0 {
+			t.Errorf("%v: IsDisabled called %d times in a frame, want none", view, calls)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package datepicker
+
+import (
+	"image"
+	"slices"
+	"time"
+
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/widget"
+)
+
+// Event is a change made by the user, returned by DatePicker.Update. It is
+// one of SelectEvent, RangeEvent, ViewChangeEvent, OpenEvent or CloseEvent.
+type Event interface {
+	isEvent()
+}
+
+// SelectEvent is sent when the selection changes: Date in SingleDate mode,
+// either end of the range in DateRange mode and Selected in MultiDate mode.
+// Paging the calendar changes none of them.
+type SelectEvent struct {
+	// Date is the DatePicker's Date after the change.
+	Date time.Time
+}
+
+// RangeEvent is sent in DateRange mode when the end of a range is picked.
+type RangeEvent struct {
+	Start, End time.Time
+}
+
+// ViewChangeEvent is sent when the calendar zooms in or out.
+type ViewChangeEvent struct {
+	View View
+}
+
+// OpenEvent is sent when the calendar opens.
+type OpenEvent struct{}
+
+// CloseEvent is sent when the calendar closes.
+type CloseEvent struct{}
+
+func (SelectEvent) isEvent()     {}
+func (RangeEvent) isEvent()      {}
+func (ViewChangeEvent) isEvent() {}
+func (OpenEvent) isEvent()       {}
+func (CloseEvent) isEvent()      {}
+
+// Update processes the input of the last frame and returns the next change
+// it made, if any. Call it until it reports false before Layout to see every
+// change exactly once; Layout discards the events nobody asked for.
+func (dp *DatePicker) Update(gtx layout.Context) (Event, bool) {
+	if len(dp.events) == 0 {
+		dp.update(gtx)
+	}
+	if len(dp.events) == 0 {
+		return nil, false
+	}
+	e := dp.events[0]
+	dp.events = dp.events[1:]
+	return e, true
+}
+
+// pickerState is the part of a DatePicker that events are reported for.
+type pickerState struct {
+	date       time.Time
+	open       bool
+	view       View
+	rangeStart time.Time
+	rangeEnd   time.Time
+	selected   []time.Time
+}
+
+func (dp *DatePicker) state() pickerState {
+	return pickerState{
+		date:       dp.Date,
+		open:       dp.IsOpen,
+		view:       dp.view,
+		rangeStart: dp.RangeStart,
+		rangeEnd:   dp.RangeEnd,
+		selected:   slices.Clone(dp.Selected),
+	}
+}
+
+// update handles the clicks, key presses and typed text of the last frame,
+// and queues an event for each change they made.
+func (dp *DatePicker) update(gtx layout.Context) {
+	dp.setDefaults()
+	dp.syncPage()
+	before := dp.state()
+
+	for {
+		ev, ok := gtx.Event(pointer.Filter{Target: &dp.popupRect, Kinds: pointer.Press})
+		if !ok {
+			break
+		}
+		e, ok := ev.(pointer.Event)
+		if !ok {
+			continue
+		}
+		// Presses on the input box are left to the editor and Openbtn.
+		p := e.Position.Round()
+		if !p.In(dp.popupRect) && !p.In(image.Rectangle{Max: dp.inputSize}) {
+			dp.IsOpen = false
+		}
+	}
+	if dp.Openbtn.Clicked(gtx) {
+		dp.IsOpen = !dp.IsOpen
+		dp.view = DayView
+		if dp.IsOpen {
+			dp.page = monthOf(dp.Date)
+			dp.cursor = dp.Date
+			gtx.Execute(key.FocusCmd{Tag: dp})
+		}
+	}
+	if dp.TodayBtn.Clicked(gtx) {
+		dp.Date = dp.clamp(dp.withTime(time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, dp.Date.Location())))
+		dp.page = monthOf(dp.Date)
+		// dp.IsOpen = false
+	}
+	dp.updateEditor(gtx)
+	dp.updateCalendar(gtx)
+	if dp.ShowTime {
+		dp.updateTime(gtx)
+	}
+	dp.syncPage()
+
+	// Only overwrite the editor when the date changed outside of it, so that
+	// whatever the user is typing survives across frames.
+	if text := dp.editorValue(); text != dp.editorText {
+		dp.Editor.SetText(text)
+		dp.editorText = text
+		dp.ParseErr = nil
+	}
+
+	dp.queueChanges(before)
+}
+
+// setDefaults fills in the fields a zero DatePicker leaves unset, so that it
+// starts out on today with weeks starting on Monday.
+func (dp *DatePicker) setDefaults() {
+	if dp.Editor == nil {
+		dp.Editor = new(widget.Editor)
+	}
+	if !dp.defaulted {
+		dp.defaulted = true
+		if dp.FirstWeekday == time.Sunday {
+			dp.FirstWeekday = time.Monday
+		}
+	}
+	if dp.Date.IsZero() {
+		now := time.Now()
+		dp.Date = dp.clamp(dp.withTime(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())))
+	}
+}
+
+// updateEditor parses the typed text on submit and when the editor loses focus.
+func (dp *DatePicker) updateEditor(gtx layout.Context) {
+	dp.Editor.SingleLine = true
+	dp.Editor.Submit = true
+	for {
+		ev, ok := dp.Editor.Update(gtx)
+		if !ok {
+			break
+		}
+		if submit, ok := ev.(widget.SubmitEvent); ok {
+			dp.commitText(submit.Text)
+		}
+	}
+	focused := gtx.Focused(dp.Editor)
+	if dp.editorFocused && !focused && dp.Editor.Text() != dp.editorText {
+		dp.commitText(dp.Editor.Text())
+	}
+	dp.editorFocused = focused
+}
+
+// updateCalendar handles the navigation buttons, the grid cells and the keys
+// of the open calendar.
+func (dp *DatePicker) updateCalendar(gtx layout.Context) {
+	if dp.PrevBtn.Clicked(gtx) && dp.canPrev() {
+		switch dp.view {
+		case DayView:
+			dp.page = dp.page.AddDate(0, -1, 0)
+		case MonthView:
+			dp.page = dp.page.AddDate(-1, 0, 0)
+		case YearView, DecadeView, CenturyView:
+			*dp.firstYear(dp.view) -= gridCells * dp.view.span()
+		}
+	}
+	if dp.NextBtn.Clicked(gtx) && dp.canNext() {
+		switch dp.view {
+		case DayView:
+			dp.page = dp.page.AddDate(0, 1, 0)
+		case MonthView:
+			dp.page = dp.page.AddDate(1, 0, 0)
+		case YearView, DecadeView, CenturyView:
+			*dp.firstYear(dp.view) += gridCells * dp.view.span()
+		}
+	}
+	// In the day grid the title is split into a month and a year button;
+	// elsewhere YearBtn is the whole title and zooms out one level.
+	if dp.MonthBtn.Clicked(gtx) {
+		dp.SetView(MonthView)
+	}
+	if dp.YearBtn.Clicked(gtx) {
+		if dp.view == DayView {
+			dp.SetView(YearView)
+		} else {
+			dp.zoomOut()
+		}
+	}
+
+	switch dp.view {
+	case DayView:
+		gridStart, rows := dp.dayGrid()
+		// Collect the clicks before acting on them, since picking a day of
+		// another month changes the cells.
+		var clicked []time.Time
+		for i := 0; i < rows*7; i++ {
+			day := gridStart.AddDate(0, 0, i)
+			if button := dp.dayButton(i, day); button != nil && button.Clicked(gtx) && !dp.dayDisabled(day) {
+				clicked = append(clicked, day)
+			}
+		}
+		for _, day := range clicked {
+			dp.selectDay(day)
+		}
+		if dp.ShowWeekNumbers && (dp.Mode == DateRange || dp.Mode == MultiDate) {
+			for row := 0; row < rows; row++ {
+				if dp.WeekBtns[row].Clicked(gtx) {
+					dp.selectWeek(dp.isoWeek(gridStart.AddDate(0, 0, 7*row)))
+				}
+			}
+		}
+	case MonthView:
+		year := dp.page.Year()
+		for i := range dp.Months {
+			if dp.Months[i].Clicked(gtx) && !dp.monthDisabled(year, time.Month(i+1)) {
+				dp.selectMonth(year, time.Month(i+1))
+			}
+		}
+	case YearView, DecadeView, CenturyView:
+		view, first, span := dp.view, *dp.firstYear(dp.view), dp.view.span()
+		cells := dp.cells(view)
+		for i := range cells {
+			if start := first + span*i; cells[i].Clicked(gtx) && !dp.spanDisabled(start, span) {
+				if view == YearView {
+					dp.selectYear(start)
+				} else {
+					dp.selectSpan(start)
+				}
+			}
+		}
+	}
+
+	dp.syncCursor()
+	for {
+		ev, ok := gtx.Event(
+			key.FocusFilter{Target: dp},
+			key.Filter{Focus: dp, Name: key.NameLeftArrow},
+			key.Filter{Focus: dp, Name: key.NameRightArrow},
+			key.Filter{Focus: dp, Name: key.NameUpArrow},
+			key.Filter{Focus: dp, Name: key.NameDownArrow},
+			key.Filter{Focus: dp, Name: key.NamePageUp, Optional: key.ModShift},
+			key.Filter{Focus: dp, Name: key.NamePageDown, Optional: key.ModShift},
+			key.Filter{Focus: dp, Name: key.NameHome},
+			key.Filter{Focus: dp, Name: key.NameEnd},
+			key.Filter{Focus: dp, Name: key.NameReturn},
+			key.Filter{Focus: dp, Name: key.NameEnter},
+			key.Filter{Focus: dp, Name: key.NameEscape},
+		)
+		if !ok {
+			break
+		}
+		if e, ok := ev.(key.Event); ok && e.State == key.Press {
+			dp.handleKey(e)
+		}
+	}
+
+	// Cells take the keyboard focus when clicked; hand it back to the
+	// calendar so the arrow keys keep working.
+	for {
+		_, ok := gtx.Event(pointer.Filter{Target: dp, Kinds: pointer.Press})
+		if !ok {
+			break
+		}
+		gtx.Execute(key.FocusCmd{Tag: dp})
+	}
+}
+
+// updateTime handles the buttons that change the time of day.
+func (dp *DatePicker) updateTime(gtx layout.Context) {
+	hour, minute, second := dp.Date.Clock()
+	if dp.HourUp.Clicked(gtx) {
+		dp.setClock(hour+1, minute, second)
+	}
+	if dp.HourDown.Clicked(gtx) {
+		dp.setClock(hour-1, minute, second)
+	}
+	if dp.MinuteUp.Clicked(gtx) {
+		dp.setClock(hour, minute+1, second)
+	}
+	if dp.MinuteDown.Clicked(gtx) {
+		dp.setClock(hour, minute-1, second)
+	}
+	if dp.SecondUp.Clicked(gtx) {
+		dp.setClock(hour, minute, second+1)
+	}
+	if dp.SecondDown.Clicked(gtx) {
+		dp.setClock(hour, minute, second-1)
+	}
+	if dp.AmPmBtn.Clicked(gtx) {
+		dp.setClock(hour+12, minute, second)
+	}
+}
+
+// queueChanges queues an event for every difference between before and the
+// current state.
+func (dp *DatePicker) queueChanges(before pickerState) {
+	if dp.IsOpen && !before.open && !dp.Inline {
+		dp.events = append(dp.events, OpenEvent{})
+	}
+	if dp.view != before.view {
+		dp.events = append(dp.events, ViewChangeEvent{View: dp.view})
+	}
+	var selected bool
+	switch dp.Mode {
+	case DateRange:
+		selected = !dp.RangeStart.Equal(before.rangeStart) || !dp.RangeEnd.Equal(before.rangeEnd)
+	case MultiDate:
+		selected = !slices.EqualFunc(dp.Selected, before.selected, time.Time.Equal)
+	default:
+		selected = !dp.Date.Equal(before.date)
+	}
+	if selected {
+		dp.events = append(dp.events, SelectEvent{Date: dp.Date})
+	}
+	if dp.Mode == DateRange && selected && !dp.RangeEnd.IsZero() {
+		dp.events = append(dp.events, RangeEvent{Start: dp.RangeStart, End: dp.RangeEnd})
+	}
+	if !dp.IsOpen && before.open && !dp.Inline {
+		dp.events = append(dp.events, CloseEvent{})
+	}
+}
@@ -0,0 +1,162 @@
+package datepicker
+
+import (
+	"time"
+
+	"gioui.org/io/key"
+)
+
+// syncCursor moves the keyboard cursor onto the page the calendar shows,
+// after the page was changed with the mouse.
+func (dp *DatePicker) syncCursor() {
+	switch dp.view {
+	case DayView:
+		if !sameMonth(dp.cursor, dp.page) {
+			dp.cursor = dp.page
+			if sameMonth(dp.Date, dp.page) {
+				dp.cursor = dp.Date
+			}
+		}
+	case MonthView:
+		if dp.cursor.Year() != dp.page.Year() {
+			dp.cursor = dp.page
+		}
+	case YearView, DecadeView, CenturyView:
+		first := *dp.firstYear(dp.view)
+		if year := dp.cursor.Year(); year < first || year >= first+gridCells*dp.view.span() {
+			dp.cursor = time.Date(first, dp.page.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
+		}
+	}
+}
+
+// handleKey moves the keyboard cursor in the open calendar, or acts on the
+// cell under it. Escape zooms in, and closes the calendar from the day grid.
+func (dp *DatePicker) handleKey(e key.Event) {
+	shift := e.Modifiers.Contain(key.ModShift)
+	switch e.Name {
+	case key.NameEscape:
+		if dp.view == DayView {
+			dp.IsOpen = false
+		} else {
+			dp.zoomIn()
+		}
+		return
+	case key.NameReturn, key.NameEnter:
+		switch dp.view {
+		case DayView:
+			if !dp.dayDisabled(dp.cursor) {
+				dp.selectDay(dp.cursor)
+			}
+		case MonthView:
+			if !dp.monthDisabled(dp.cursor.Year(), dp.cursor.Month()) {
+				dp.selectMonth(dp.cursor.Year(), dp.cursor.Month())
+			}
+		case YearView:
+			if !dp.yearDisabled(dp.cursor.Year()) {
+				dp.selectYear(dp.cursor.Year())
+			}
+		case DecadeView, CenturyView:
+			if start := startOf(dp.cursor.Year(), dp.view.span()); !dp.spanDisabled(start, dp.view.span()) {
+				dp.selectSpan(start)
+			}
+		}
+		return
+	}
+
+	switch dp.view {
+	case DayView:
+		cursor := dp.cursor
+		// column is the position of the cursor within its week row.
+		column := (int(cursor.Weekday()) - int(dp.FirstWeekday) + 7) % 7
+		switch e.Name {
+		case key.NameLeftArrow:
+			cursor = cursor.AddDate(0, 0, -1)
+		case key.NameRightArrow:
+			cursor = cursor.AddDate(0, 0, 1)
+		case key.NameUpArrow:
+			cursor = cursor.AddDate(0, 0, -7)
+		case key.NameDownArrow:
+			cursor = cursor.AddDate(0, 0, 7)
+		case key.NamePageUp:
+			if shift {
+				cursor = addYears(cursor, -1)
+			} else {
+				cursor = addMonths(cursor, -1)
+			}
+		case key.NamePageDown:
+			if shift {
+				cursor = addYears(cursor, 1)
+			} else {
+				cursor = addMonths(cursor, 1)
+			}
+		case key.NameHome:
+			cursor = cursor.AddDate(0, 0, -column)
+		case key.NameEnd:
+			cursor = cursor.AddDate(0, 0, 6-column)
+		}
+		if dp.outOfBounds(cursor) {
+			return
+		}
+		dp.cursor = cursor
+		dp.page = monthOf(cursor)
+	case MonthView:
+		month := int(dp.cursor.Month()) - 1
+		switch e.Name {
+		case key.NameLeftArrow:
+			month--
+		case key.NameRightArrow:
+			month++
+		case key.NameUpArrow:
+			month -= 4
+		case key.NameDownArrow:
+			month += 4
+		case key.NamePageUp:
+			month -= 12
+		case key.NamePageDown:
+			month += 12
+		case key.NameHome:
+			month -= month % 4
+		case key.NameEnd:
+			month += 3 - month%4
+		}
+		cursor := time.Date(dp.cursor.Year(), time.Month(month+1), 1, 0, 0, 0, 0, dp.Date.Location())
+		if dp.yearDisabled(cursor.Year()) {
+			return
+		}
+		dp.cursor = cursor
+		dp.page = time.Date(cursor.Year(), dp.page.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
+	case YearView, DecadeView, CenturyView:
+		first, span := dp.firstYear(dp.view), dp.view.span()
+		cell := (startOf(dp.cursor.Year(), span) - *first) / span
+		column := cell % 4
+		switch e.Name {
+		case key.NameLeftArrow:
+			cell--
+		case key.NameRightArrow:
+			cell++
+		case key.NameUpArrow:
+			cell -= 4
+		case key.NameDownArrow:
+			cell += 4
+		case key.NamePageUp:
+			cell -= gridCells
+		case key.NamePageDown:
+			cell += gridCells
+		case key.NameHome:
+			cell -= column
+		case key.NameEnd:
+			cell += 3 - column
+		}
+		start := *first + span*cell
+		if dp.spanDisabled(start, span) {
+			return
+		}
+		dp.cursor = time.Date(start, dp.cursor.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
+		for start < *first {
+			*first -= gridCells * span
+		}
+		for start >= *first+gridCells*span {
+			*first += gridCells * span
+		}
+	}
+}
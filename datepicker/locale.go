@@ -0,0 +1,235 @@
+package datepicker
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale holds the month and weekday names and the labels shown by the
+// date picker. Months are indexed from January and weekdays from Sunday,
+// matching time.Month-1 and time.Weekday.
+type Locale struct {
+	Months         [12]string
+	ShortMonths    [12]string
+	Weekdays       [7]string
+	ShortWeekdays  [7]string
+	NarrowWeekdays [7]string // single letter headers for narrow day grids
+	Today          string    // label of the button that jumps to today
+	Week           string    // header of the week number column
+
+	// DateLayout and MonthLayout spell out a day and a month, as time
+	// layouts, for screen readers.
+	DateLayout  string
+	MonthLayout string
+	// Screen reader descriptions of cell states and calendar controls.
+	Selected      string
+	Unavailable   string
+	PrevMonth     string
+	NextMonth     string
+	PrevYear      string
+	NextYear      string
+	PrevYears     string
+	NextYears     string
+	ChooseMonth   string
+	ChooseYear    string
+	ChooseDecade  string
+	ChooseCentury string
+	OpenCalendar  string
+}
+
+var English = Locale{
+	Months:         [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths:    [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:       [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortWeekdays:  [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	NarrowWeekdays: [7]string{"S", "M", "T", "W", "T", "F", "S"},
+	Today:          "Go to Today",
+	Week:           "Wk",
+	DateLayout:     "Monday, 2 January 2006",
+	MonthLayout:    "January 2006",
+	Selected:       "selected",
+	Unavailable:    "unavailable",
+	PrevMonth:      "Previous month",
+	NextMonth:      "Next month",
+	PrevYear:       "Previous year",
+	NextYear:       "Next year",
+	PrevYears:      "Previous years",
+	NextYears:      "Next years",
+	ChooseMonth:    "Choose month",
+	ChooseYear:     "Choose year",
+	ChooseDecade:   "Choose decade",
+	ChooseCentury:  "Choose century",
+	OpenCalendar:   "Open calendar",
+}
+
+var French = Locale{
+	Months:         [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	ShortMonths:    [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	Weekdays:       [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	ShortWeekdays:  [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	NarrowWeekdays: [7]string{"D", "L", "M", "M", "J", "V", "S"},
+	Today:          "Aujourd'hui",
+	Week:           "Sem.",
+	DateLayout:     "Monday 2 January 2006",
+	MonthLayout:    "January 2006",
+	Selected:       "sélectionné",
+	Unavailable:    "indisponible",
+	PrevMonth:      "Mois précédent",
+	NextMonth:      "Mois suivant",
+	PrevYear:       "Année précédente",
+	NextYear:       "Année suivante",
+	PrevYears:      "Années précédentes",
+	NextYears:      "Années suivantes",
+	ChooseMonth:    "Choisir le mois",
+	ChooseYear:     "Choisir l'année",
+	ChooseDecade:   "Choisir la décennie",
+	ChooseCentury:  "Choisir le siècle",
+	OpenCalendar:   "Ouvrir le calendrier",
+}
+
+var German = Locale{
+	Months:         [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	ShortMonths:    [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sep.", "Okt.", "Nov.", "Dez."},
+	Weekdays:       [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	ShortWeekdays:  [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	NarrowWeekdays: [7]string{"S", "M", "D", "M", "D", "F", "S"},
+	Today:          "Heute",
+	Week:           "KW",
+	DateLayout:     "Monday, 2. January 2006",
+	MonthLayout:    "January 2006",
+	Selected:       "ausgewählt",
+	Unavailable:    "nicht verfügbar",
+	PrevMonth:      "Vorheriger Monat",
+	NextMonth:      "Nächster Monat",
+	PrevYear:       "Vorheriges Jahr",
+	NextYear:       "Nächstes Jahr",
+	PrevYears:      "Vorherige Jahre",
+	NextYears:      "Nächste Jahre",
+	ChooseMonth:    "Monat wählen",
+	ChooseYear:     "Jahr wählen",
+	ChooseDecade:   "Jahrzehnt wählen",
+	ChooseCentury:  "Jahrhundert wählen",
+	OpenCalendar:   "Kalender öffnen",
+}
+
+var Japanese = Locale{
+	Months:         [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	ShortMonths:    [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	Weekdays:       [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+	ShortWeekdays:  [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	NarrowWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	Today:          "今日",
+	Week:           "週",
+	DateLayout:     "2006年January2日 Monday",
+	MonthLayout:    "2006年January",
+	Selected:       "選択済み",
+	Unavailable:    "選択不可",
+	PrevMonth:      "前の月",
+	NextMonth:      "次の月",
+	PrevYear:       "前の年",
+	NextYear:       "次の年",
+	PrevYears:      "前の期間",
+	NextYears:      "次の期間",
+	ChooseMonth:    "月を選択",
+	ChooseYear:     "年を選択",
+	ChooseDecade:   "年代を選択",
+	ChooseCentury:  "世紀を選択",
+	OpenCalendar:   "カレンダーを開く",
+}
+
+var Arabic = Locale{
+	Months:         [12]string{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"},
+	ShortMonths:    [12]string{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو", "أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"},
+	Weekdays:       [7]string{"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"},
+	ShortWeekdays:  [7]string{"أحد", "اثنين", "ثلاثاء", "أربعاء", "خميس", "جمعة", "سبت"},
+	NarrowWeekdays: [7]string{"ح", "ن", "ث", "ر", "خ", "ج", "س"},
+	Today:          "اليوم",
+	Week:           "أسبوع",
+	DateLayout:     "Monday، 2 January 2006",
+	MonthLayout:    "January 2006",
+	Selected:       "محدد",
+	Unavailable:    "غير متاح",
+	PrevMonth:      "الشهر السابق",
+	NextMonth:      "الشهر التالي",
+	PrevYear:       "السنة السابقة",
+	NextYear:       "السنة التالية",
+	PrevYears:      "السنوات السابقة",
+	NextYears:      "السنوات التالية",
+	ChooseMonth:    "اختر الشهر",
+	ChooseYear:     "اختر السنة",
+	ChooseDecade:   "اختر العقد",
+	ChooseCentury:  "اختر القرن",
+	OpenCalendar:   "افتح التقويم",
+}
+
+// nameElements swaps the name elements of a layout for control characters,
+// which time.Format copies as is.
+var nameElements = strings.NewReplacer("January", "\x01", "Jan", "\x02", "Monday", "\x03", "Mon", "\x04")
+
+// Format is like time.Time.Format, with the month and weekday names of
+// the layout taken from the locale.
+func (l *Locale) Format(t time.Time, layout string) string {
+	// Put the localized names in place of the control characters afterwards.
+	layout = nameElements.Replace(layout)
+	return strings.NewReplacer(
+		"\x01", l.Months[t.Month()-1],
+		"\x02", l.ShortMonths[t.Month()-1],
+		"\x03", l.Weekdays[t.Weekday()],
+		"\x04", l.ShortWeekdays[t.Weekday()],
+	).Replace(t.Format(layout))
+}
+
+// Parse is like time.ParseInLocation, accepting the locale's month and
+// weekday names wherever the layout has English ones. Names are matched
+// without regard to case.
+func (l *Locale) Parse(layout, value string, loc *time.Location) (time.Time, error) {
+	type name struct{ local, english string }
+	var names []name
+	fullMonths := strings.Contains(layout, "January")
+	for i := range l.Months {
+		english := English.ShortMonths[i]
+		if fullMonths {
+			english = English.Months[i]
+		}
+		names = append(names, name{l.Months[i], english}, name{l.ShortMonths[i], english})
+	}
+	// Weekday names are only looked for when the layout has them, since
+	// some locales reuse them inside month names.
+	if strings.Contains(layout, "Mon") {
+		fullWeekdays := strings.Contains(layout, "Monday")
+		for i := range l.Weekdays {
+			english := English.ShortWeekdays[i]
+			if fullWeekdays {
+				english = English.Weekdays[i]
+			}
+			names = append(names, name{l.Weekdays[i], english}, name{l.ShortWeekdays[i], english})
+		}
+	}
+	// Names that the layout has as plain text are left alone, like the 日
+	// (day) of Japanese dates, which is also short for Sunday.
+	literals := nameElements.Replace(layout)
+	names = slices.DeleteFunc(names, func(n name) bool {
+		return n.local == "" || strings.Contains(literals, n.local)
+	})
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i].local) > len(names[j].local) })
+
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		matched := false
+		for _, n := range names {
+			if len(value)-i >= len(n.local) && strings.EqualFold(value[i:i+len(n.local)], n.local) {
+				b.WriteString(n.english)
+				i += len(n.local)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(value[i])
+			i++
+		}
+	}
+	return time.ParseInLocation(layout, b.String(), loc)
+}
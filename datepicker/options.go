@@ -0,0 +1,84 @@
+package datepicker
+
+import (
+	"time"
+
+	"gioui.org/widget"
+)
+
+// Option configures a DatePicker made by NewDatePicker.
+type Option func(*DatePicker)
+
+// NewDatePicker returns a DatePicker showing today, configured by opts.
+func NewDatePicker(opts ...Option) *DatePicker {
+	now := time.Now()
+	dp := &DatePicker{
+		Date:         time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		Editor:       new(widget.Editor),
+		FirstWeekday: time.Monday,
+		defaulted:    true,
+	}
+	for _, opt := range opts {
+		opt(dp)
+	}
+	if loc := dp.location; loc != nil {
+		d := dp.Date
+		dp.Date = time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), d.Second(), 0, loc)
+	}
+	dp.Date = dp.clamp(dp.Date)
+	return dp
+}
+
+// WithDate sets the initial date.
+func WithDate(date time.Time) Option {
+	return func(dp *DatePicker) {
+		dp.Date = date
+	}
+}
+
+// WithLocation sets the time zone that picked dates are in. The initial date
+// keeps its calendar day and time of day, whichever order the options are in.
+func WithLocation(loc *time.Location) Option {
+	return func(dp *DatePicker) {
+		dp.location = loc
+	}
+}
+
+// WithBounds sets MinDate and MaxDate. A zero value leaves that side
+// unbounded.
+func WithBounds(min, max time.Time) Option {
+	return func(dp *DatePicker) {
+		dp.MinDate = min
+		dp.MaxDate = max
+	}
+}
+
+// WithFormat sets the layout of the input box, and further layouts accepted
+// when typing a date.
+func WithFormat(format string, inputFormats ...string) Option {
+	return func(dp *DatePicker) {
+		dp.Format = format
+		dp.InputFormats = inputFormats
+	}
+}
+
+// WithFirstWeekday sets the weekday that starts the rows of the day grid.
+func WithFirstWeekday(day time.Weekday) Option {
+	return func(dp *DatePicker) {
+		dp.FirstWeekday = day
+	}
+}
+
+// WithLocale sets the names and labels shown by the picker.
+func WithLocale(l *Locale) Option {
+	return func(dp *DatePicker) {
+		dp.Locale = l
+	}
+}
+
+// WithTheme sets the colors of the picker.
+func WithTheme(t *Theme) Option {
+	return func(dp *DatePicker) {
+		dp.Theme = t
+	}
+}
@@ -0,0 +1,69 @@
+package datepicker
+
+import (
+	"image/color"
+
+	"github.com/hd-buddy/GioCalendarPicker/util"
+)
+
+// Theme holds the colors of a DatePicker.
+type Theme struct {
+	Background color.NRGBA // popup background
+	Border     color.NRGBA // input box, popup and button borders
+	Shadow     color.NRGBA // outer rings drawn around the popup
+	Text       color.NRGBA // input text and calendar cells
+	Weekend    color.NRGBA // Saturday and Sunday cells
+	Header     color.NRGBA // navigation buttons and weekday names
+	Footer     color.NRGBA // footer text
+	Today      color.NRGBA // today's cell and the button that jumps to it
+	Selected   color.NRGBA // border of selected cells
+	Range      color.NRGBA // background of days inside a picked range
+	Hover      color.NRGBA // background of cells under the pointer
+	Focus      color.NRGBA // border of the keyboard cursor cell
+	Disabled   color.NRGBA // text of cells that cannot be picked
+	Adjacent   color.NRGBA // text of days of the previous and next month
+	// DisabledBackground is the background of cells that cannot be picked.
+	DisabledBackground color.NRGBA
+	Error              color.NRGBA // input border while the typed text is invalid
+	TodayMarker        color.NRGBA // dot under today's cell, selected or not
+}
+
+var LightTheme = Theme{
+	Background:         util.WhiteColor,
+	Border:             util.GrayColor,
+	Shadow:             util.MGrayColor,
+	Text:               util.BlackColor,
+	Weekend:            util.DarkRedColor,
+	Header:             util.BlackColor,
+	Footer:             util.BlackColor,
+	Today:              color.NRGBA{R: 0, G: 0, B: 255, A: 255},
+	TodayMarker:        color.NRGBA{R: 0, G: 0, B: 255, A: 255},
+	Selected:           util.RedColor,
+	Range:              color.NRGBA{R: 214, G: 228, B: 255, A: 255},
+	Hover:              util.MGrayColor,
+	Focus:              color.NRGBA{R: 0, G: 0, B: 255, A: 255},
+	Disabled:           util.GrayColor,
+	Adjacent:           color.NRGBA{R: 170, G: 170, B: 170, A: 255},
+	DisabledBackground: util.LGrayColor,
+	Error:              util.RedColor,
+}
+
+var DarkTheme = Theme{
+	Background:         util.DarkGrayColor,
+	Border:             util.GrayColor,
+	Shadow:             util.GrayShadow_high,
+	Text:               util.WhiteColor,
+	Weekend:            color.NRGBA{R: 255, G: 138, B: 128, A: 255},
+	Header:             util.WhiteColor,
+	Footer:             util.MGrayColor,
+	Today:              color.NRGBA{R: 130, G: 177, B: 255, A: 255},
+	TodayMarker:        color.NRGBA{R: 130, G: 177, B: 255, A: 255},
+	Selected:           color.NRGBA{R: 255, G: 99, B: 99, A: 255},
+	Range:              color.NRGBA{R: 40, G: 66, B: 110, A: 255},
+	Hover:              color.NRGBA{R: 62, G: 70, B: 84, A: 255},
+	Focus:              color.NRGBA{R: 130, G: 177, B: 255, A: 255},
+	Disabled:           util.GrayTextColor,
+	Adjacent:           color.NRGBA{R: 120, G: 130, B: 150, A: 255},
+	DisabledBackground: color.NRGBA{R: 44, G: 50, B: 61, A: 255},
+	Error:              color.NRGBA{R: 255, G: 99, B: 99, A: 255},
+}
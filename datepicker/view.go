@@ -0,0 +1,156 @@
+package datepicker
+
+import (
+	"time"
+
+	"gioui.org/widget"
+)
+
+// View is a zoom level of the open calendar. Zooming out goes from DayView
+// through MonthView, YearView and DecadeView to CenturyView, and zooming in
+// goes back.
+type View int
+
+const (
+	DayView     View = iota // the days of the month of Date
+	MonthView               // the months of the year of Date
+	YearView                // 20 years from YearRange
+	DecadeView              // 20 decades from DecadeRange
+	CenturyView             // 20 centuries from CenturyRange
+)
+
+// gridCells is the number of cells in the year, decade and century grids.
+const gridCells = 20
+
+func (v View) String() string {
+	switch v {
+	case DayView:
+		return "DayView"
+	case MonthView:
+		return "MonthView"
+	case YearView:
+		return "YearView"
+	case DecadeView:
+		return "DecadeView"
+	case CenturyView:
+		return "CenturyView"
+	default:
+		return "View(invalid)"
+	}
+}
+
+// span returns the number of years in a cell of the grid of v, or 0 for the
+// day and month grids.
+func (v View) span() int {
+	switch v {
+	case YearView:
+		return 1
+	case DecadeView:
+		return 10
+	case CenturyView:
+		return 100
+	}
+	return 0
+}
+
+// View returns the grid the calendar shows.
+func (dp *DatePicker) View() View {
+	return dp.view
+}
+
+// SetView shows v in the calendar, paged to the month shown. Values other
+// than the View constants are ignored.
+func (dp *DatePicker) SetView(v View) {
+	dp.syncPage()
+	switch v {
+	case DayView, MonthView:
+	case YearView, DecadeView, CenturyView:
+		dp.pageTo(v, dp.page.Year())
+	default:
+		return
+	}
+	dp.view = v
+}
+
+// pageTo pages the grid of v so that the cell of year is near its middle.
+func (dp *DatePicker) pageTo(v View, year int) {
+	span := v.span()
+	*dp.firstYear(v) = startOf(year, span) - gridCells/2*span
+}
+
+// firstYear returns the field holding the first year of the grid of v, or
+// nil for the day and month grids.
+func (dp *DatePicker) firstYear(v View) *int {
+	switch v {
+	case YearView:
+		return &dp.YearRange
+	case DecadeView:
+		return &dp.DecadeRange
+	case CenturyView:
+		return &dp.CenturyRange
+	}
+	return nil
+}
+
+// cells returns the buttons of the grid of v, allocating them on first use.
+func (dp *DatePicker) cells(v View) []widget.Clickable {
+	var cells *[]widget.Clickable
+	switch v {
+	case YearView:
+		cells = &dp.Years
+	case DecadeView:
+		cells = &dp.Decades
+	case CenturyView:
+		cells = &dp.Centuries
+	default:
+		return nil
+	}
+	if n := len(*cells); n < gridCells {
+		*cells = append(*cells, make([]widget.Clickable, gridCells-n)...)
+	}
+	return *cells
+}
+
+// zoomOut shows the next coarser grid, around the page shown now.
+func (dp *DatePicker) zoomOut() {
+	switch dp.view {
+	case DayView:
+		dp.view = MonthView
+	case MonthView:
+		dp.SetView(YearView)
+	case YearView, DecadeView:
+		span := dp.view.span()
+		middle := *dp.firstYear(dp.view) + gridCells/2*span
+		dp.view++
+		dp.pageTo(dp.view, middle)
+	}
+}
+
+// zoomIn goes back to the next finer grid. The decade and year grids open
+// around the cell under the keyboard cursor; the month and day grids show
+// Date, as they always do.
+func (dp *DatePicker) zoomIn() {
+	switch dp.view {
+	case MonthView:
+		dp.view = DayView
+	case YearView:
+		dp.view = MonthView
+	case DecadeView, CenturyView:
+		start := startOf(dp.cursor.Year(), dp.view.span())
+		dp.view--
+		*dp.firstYear(dp.view) = start
+	}
+}
+
+// selectSpan shows the next finer grid from the decade or century starting
+// in year start.
+func (dp *DatePicker) selectSpan(start int) {
+	dp.view--
+	*dp.firstYear(dp.view) = start
+	dp.cursor = time.Date(start, dp.page.Month(), 1, 0, 0, 0, 0, dp.Date.Location())
+}
+
+// startOf returns the first year of the span of years that year falls in.
+func startOf(year, span int) int {
+	return year - ((year%span)+span)%span
+}
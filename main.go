@@ -1,9 +1,9 @@
 package main
 
 import (
+	"image"
 	"log"
 	"os"
-	"time"
 
 	"gioui.org/app"
 	"gioui.org/font"
@@ -11,14 +11,12 @@ import (
 	"gioui.org/op"
 	"gioui.org/text"
 	"gioui.org/unit"
-	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"github.com/hd-buddy/GioCalendarPicker/datepicker"
 	"github.com/hd-buddy/GioCalendarPicker/util"
 )
 
 func main() {
-
 	go func() {
 		w := new(app.Window)
 
@@ -31,22 +29,7 @@ func main() {
 	app.Main()
 }
 
-var dp = datepicker.DatePicker{
-	Date:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.Time.Location(time.Now())),
-	Openbtn:   widget.Clickable{},
-	IsOpen:    false,
-	PrevBtn:   widget.Clickable{},
-	NextBtn:   widget.Clickable{},
-	Days:      [31]widget.Clickable{},
-	MonthBtn:  widget.Clickable{},
-	YearBtn:   widget.Clickable{},
-	Months:    [12]widget.Clickable{},
-	Years:     [20]widget.Clickable{},
-	ViewMode:  "date", // "date", "month", or "year"
-	YearRange: time.Now().Year() - 10,
-	TodayBtn: widget.Clickable{},
-	Editor:    &widget.Editor{},
-}
+var dp = datepicker.NewDatePicker()
 
 type (
 	C = layout.Context
@@ -69,13 +52,15 @@ func Display(w *app.Window) error {
 }
 
 func DisplayDatePicker(gtx C, th *material.Theme) D {
+	window := gtx.Constraints.Max
+	var titleHeight int
 	return layout.Flex{
 		Axis:      layout.Vertical,
 		Alignment: layout.Middle,
 		Spacing:   layout.SpaceEnd,
 	}.Layout(gtx,
 		layout.Rigid(func(gtx C) D {
-			return util.LayoutText(gtx, th, util.Text{
+			dims := util.LayoutText(gtx, th, util.Text{
 				Text:       "Date Picker in Gio",
 				Size:       20,
 				TextColor:  util.BlackColor,
@@ -83,9 +68,14 @@ func DisplayDatePicker(gtx C, th *material.Theme) D {
 				Inset:      layout.Inset{Top: 10, Bottom: 10},
 				Alignment:  text.Middle,
 			})
+			titleHeight = dims.Size.Y
+			return dims
 		}),
 		layout.Rigid(func(gtx C) D {
 			return layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx C) D {
+				// Keep the popup inside the window.
+				origin := image.Pt(gtx.Dp(10), titleHeight+gtx.Dp(10))
+				dp.Bounds = image.Rectangle{Max: window}.Sub(origin)
 				return dp.Layout(gtx, th)
 			})
 		}),
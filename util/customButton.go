@@ -31,6 +31,7 @@ type CustomButtonStyle struct {
 	CornerRadius unit.Dp
 	Inset        layout.Inset
 	Button       *widget.Clickable
+	Description  string // read out by screen readers
 	shaper       *text.Shaper
 }
 
@@ -39,6 +40,7 @@ type CustomButtonLayoutStyle struct {
 	Background   color.NRGBA
 	CornerRadius unit.Dp
 	Button       *widget.Clickable
+	Description  string
 }
 
 // CustomButton initializes a CustomButtonStyle.
@@ -72,6 +74,7 @@ func (b CustomButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
 		Background:   b.Background,
 		CornerRadius: b.CornerRadius,
 		Button:       b.Button,
+		Description:  b.Description,
 	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return b.Inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 			colMacro := op.Record(gtx.Ops)
@@ -85,6 +88,10 @@ func (b CustomButtonStyle) Layout(gtx layout.Context) layout.Dimensions {
 func (b CustomButtonLayoutStyle) Layout(gtx layout.Context, w layout.Widget) layout.Dimensions {
 	min := gtx.Constraints.Min
 	return b.Button.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		semantic.Button.Add(gtx.Ops)
+		if b.Description != "" {
+			semantic.DescriptionOp(b.Description).Add(gtx.Ops)
+		}
 		return layout.Background{}.Layout(gtx,
 			func(gtx layout.Context) layout.Dimensions {
 				rr := gtx.Dp(b.CornerRadius)
@@ -112,9 +119,17 @@ func (b CustomButtonLayoutStyle) Layout(gtx layout.Context, w layout.Widget) lay
 }
 
 func Clickable(gtx layout.Context, button *widget.Clickable, w layout.Widget) layout.Dimensions {
+	return DescribedClickable(gtx, button, "", w)
+}
+
+// DescribedClickable is Clickable with a description for screen readers.
+func DescribedClickable(gtx layout.Context, button *widget.Clickable, description string, w layout.Widget) layout.Dimensions {
 
 	return button.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		semantic.Button.Add(gtx.Ops)
+		if description != "" {
+			semantic.DescriptionOp(description).Add(gtx.Ops)
+		}
 		return layout.Background{}.Layout(gtx,
 			func(gtx layout.Context) layout.Dimensions {
 				defer clip.Rect{Max: gtx.Constraints.Min}.Push(gtx.Ops).Pop()
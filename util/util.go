@@ -215,7 +215,7 @@ func LayoutInputBoxWithIcon(gtx C, th *mt, inputBox InputBox, icon Icon, iconPos
 				Height := int(float32(icon.Height))
 				gtx.Constraints = layout.Exact(image.Pt(Width, Height))
 				if icon.ToggleIcon {
-					return Clickable(gtx, icon.IconButton, func(gtx C) D {
+					return DescribedClickable(gtx, icon.IconButton, icon.Description, func(gtx C) D {
 						if !icon.ToggleCondition {
 							return icon.Icon1.Layout(gtx)
 						} else {
@@ -223,7 +223,7 @@ func LayoutInputBoxWithIcon(gtx C, th *mt, inputBox InputBox, icon Icon, iconPos
 						}
 					})
 				} else {
-					return Clickable(gtx, icon.IconButton, func(gtx C) D {
+					return DescribedClickable(gtx, icon.IconButton, icon.Description, func(gtx C) D {
 						return icon.Icon1.Layout(gtx)
 					})
 				}
@@ -242,6 +242,7 @@ func LayoutButton(gtx C, th *mt, button Button) D {
 		Left:   unit.Dp(button.OutInset.Left),
 	}.Layout(gtx, func(gtx C) D {
 		element := CustomButton(th, button.Button, button.Text)
+		element.Description = button.Description
 		element.TextSize = unit.Sp(button.Size)
 		element.Color = button.TextColor
 		element.Font = font.Font{Typeface: "Nunito", Weight: button.FontWeight}
@@ -382,6 +383,7 @@ type Button struct {
 	Size            int
 	Text            string
 	TextColor       color.NRGBA
+	Description     string
 }
 
 type Icon struct {
@@ -393,6 +395,7 @@ type Icon struct {
 	ToggleCondition bool
 	ToggleIcon      bool
 	Width           int
+	Description     string
 }
 
 type InputBox struct {